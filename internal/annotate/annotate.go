@@ -0,0 +1,43 @@
+// Package annotate implements adding notes taken while presenting back into
+// the source of a slideshow
+package annotate
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNoSlide   = errors.New("could not find slide in file")
+	ErrEmptyNote = errors.New("note is empty")
+)
+
+// Append adds the note as a <!-- todo: --> comment to the end of the slide
+// at index in the content, where slides are separated by delimiter. The rest
+// of the content is left untouched.
+func Append(content, delimiter string, index int, note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return "", ErrEmptyNote
+	}
+	// Prevent the note from closing the comment early
+	note = strings.ReplaceAll(note, "-->", "- ->")
+
+	// A leading delimiter does not start a slide
+	var prefix string
+	if leading := strings.TrimPrefix(delimiter, "\n"); strings.HasPrefix(content, leading) {
+		prefix = leading
+		content = strings.TrimPrefix(content, leading)
+	}
+
+	slides := strings.Split(content, delimiter)
+	if index < 0 || index >= len(slides) {
+		return "", ErrNoSlide
+	}
+
+	slide := slides[index]
+	trimmed := strings.TrimRight(slide, "\n")
+	slides[index] = trimmed + "\n\n<!-- todo: " + note + " -->" + slide[len(trimmed):]
+
+	return prefix + strings.Join(slides, delimiter), nil
+}
@@ -0,0 +1,42 @@
+package annotate_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/annotate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppend(t *testing.T) {
+	content := "---\ntheme: dark\n---\n# One\n\n---\n# Two\n"
+
+	tests := []struct {
+		name    string
+		index   int
+		note    string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "Middle slide",
+			index: 1,
+			note:  "mention benchmarks",
+			want:  "---\ntheme: dark\n---\n# One\n\n<!-- todo: mention benchmarks -->\n\n---\n# Two\n",
+		},
+		{
+			name:  "Last slide",
+			index: 2,
+			note:  " fix typo -->",
+			want:  "---\ntheme: dark\n---\n# One\n\n---\n# Two\n\n<!-- todo: fix typo - -> -->\n",
+		},
+		{name: "Missing slide", index: 3, note: "note", wantErr: annotate.ErrNoSlide},
+		{name: "Empty note", index: 1, note: "  ", wantErr: annotate.ErrEmptyNote},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := annotate.Append(content, "\n---\n", tt.index, tt.note)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Package announce implements publishing the current slide to a file or Unix
+// socket, so that other programs such as stream overlays, or another instance
+// of slides, can follow along
+package announce
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"time"
+
+	"github.com/maaslalani/slides/internal/file"
+)
+
+// Slide is the current slide as it is published
+type Slide struct {
+	Page  int    `json:"page"`
+	Total int    `json:"total"`
+	Title string `json:"title"`
+}
+
+// Announcer publishes the current slide to a path in the background. A file
+// is replaced with the latest slide while a socket receives a line for every
+// slide.
+type Announcer struct {
+	path    string
+	updates chan Slide
+}
+
+// pending is the number of updates which can wait to be written before new
+// updates are dropped
+const pending = 16
+
+// New creates an announcer publishing to the path
+func New(path string) *Announcer {
+	a := &Announcer{path: path, updates: make(chan Slide, pending)}
+	go a.run()
+	return a
+}
+
+// Announce publishes the slide without blocking, the slide is dropped if too
+// many updates are waiting to be written
+func (a *Announcer) Announce(s Slide) {
+	select {
+	case a.updates <- s:
+	default:
+	}
+}
+
+func (a *Announcer) run() {
+	for s := range a.updates {
+		// Failures are ignored, presenting should never be interrupted by
+		// an overlay which isn't listening
+		_ = Write(a.path, s)
+	}
+}
+
+// Write publishes the slide as a line of JSON to the path, which is either a
+// Unix socket or a file
+func Write(path string, s Slide) error {
+	line, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return os.WriteFile(path, line, 0o644)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return file.Write(path, line)
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_ = conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_, err = conn.Write(line)
+	return err
+}
+
+// Listener receives the slides published to a Unix socket, so that another
+// instance of slides can follow along
+type Listener struct {
+	listener net.Listener
+	// Slides receives every slide published to the socket, slides are
+	// dropped if too many are waiting to be received
+	Slides chan Slide
+}
+
+// Listen creates a Unix socket at the path and receives the slides published
+// to it in the background. A socket left behind at the path is replaced.
+func Listen(path string) (*Listener, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(path)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	listener := &Listener{listener: l, Slides: make(chan Slide, pending)}
+	go listener.run()
+	return listener, nil
+}
+
+// Close stops listening and removes the socket
+func (l *Listener) Close() error {
+	return l.listener.Close()
+}
+
+func (l *Listener) run() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			return
+		}
+		go l.read(conn)
+	}
+}
+
+// read receives the lines of JSON written to the connection, lines which
+// aren't slides are ignored
+func (l *Listener) read(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var s Slide
+		if json.Unmarshal(scanner.Bytes(), &s) != nil {
+			continue
+		}
+		select {
+		case l.Slides <- s:
+		default:
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package announce_test
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maaslalani/slides/internal/announce"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slide.json")
+
+	assert.NoError(t, announce.Write(path, announce.Slide{Page: 1, Total: 3, Title: "Welcome"}))
+	assert.NoError(t, announce.Write(path, announce.Slide{Page: 2, Total: 3, Title: "Agenda"}))
+
+	got, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"page\":2,\"total\":3,\"title\":\"Agenda\"}\n", string(got))
+}
+
+func TestWrite_socket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slides.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skip("unix sockets are not supported")
+	}
+	defer l.Close()
+
+	lines := make(chan string)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+	}()
+
+	assert.NoError(t, announce.Write(path, announce.Slide{Page: 1, Total: 3, Title: "Welcome"}))
+	assert.Equal(t, "{\"page\":1,\"total\":3,\"title\":\"Welcome\"}\n", <-lines)
+}
+
+func TestListen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slides.sock")
+	l, err := announce.Listen(path)
+	if err != nil {
+		t.Skip("unix sockets are not supported")
+	}
+	defer l.Close()
+
+	assert.NoError(t, announce.Write(path, announce.Slide{Page: 2, Total: 3, Title: "Agenda"}))
+	assert.Equal(t, announce.Slide{Page: 2, Total: 3, Title: "Agenda"}, <-l.Slides)
+}
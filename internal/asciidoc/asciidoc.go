@@ -0,0 +1,113 @@
+// Package asciidoc implements converting AsciiDoc documents to markdown so
+// they can be presented as slides
+package asciidoc
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PageBreak is the AsciiDoc syntax separating slides
+const PageBreak = "<<<"
+
+// Attributes from the document header which are converted to metadata
+var metadata = []string{"author", "date", "theme", "paging"}
+
+var (
+	reAttribute = regexp.MustCompile(`^:([\w-]+):\s*(.*)$`)
+	reHeading   = regexp.MustCompile(`^(={1,6})\s+(.*)$`)
+	reSource    = regexp.MustCompile(`^\[source(?:,\s*([\w+-]+))?.*\]$`)
+	reOrdered   = regexp.MustCompile(`^(\.+)\s+(.*)$`)
+	reUnordered = regexp.MustCompile(`^(\*+)\s+(.*)$`)
+	reLink      = regexp.MustCompile(`(https?://[^\s\[]+)\[([^\]]*)\]`)
+	reBold      = regexp.MustCompile(`(^|[^\w*])\*([^*\s](?:[^*]*[^*\s])?)\*([^\w*]|$)`)
+	reItalic    = regexp.MustCompile(`(^|[^\w_])_([^_\s](?:[^_]*[^_\s])?)_([^\w_]|$)`)
+)
+
+// IsAsciiDoc returns whether the file name refers to an AsciiDoc document
+func IsAsciiDoc(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	return ext == ".adoc" || ext == ".asciidoc"
+}
+
+// ToMarkdown converts an AsciiDoc document to markdown slides separated by
+// delimiter. Page breaks start new slides and supported header attributes
+// become the slides' metadata.
+func ToMarkdown(doc, delimiter string) string {
+	var (
+		header   []string
+		lines    []string
+		language string
+		fence    string
+		inHeader = true
+	)
+
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Copy delimited blocks verbatim
+		if fence != "" {
+			if trimmed == fence {
+				lines = append(lines, "```")
+				fence = ""
+			} else {
+				lines = append(lines, line)
+			}
+			continue
+		}
+
+		if inHeader {
+			if match := reAttribute.FindStringSubmatch(trimmed); match != nil {
+				for _, key := range metadata {
+					if key == match[1] {
+						header = append(header, fmt.Sprintf("%s: %q", key, match[2]))
+					}
+				}
+				continue
+			}
+			if trimmed != "" && !strings.HasPrefix(trimmed, "=") {
+				inHeader = false
+			}
+		}
+
+		switch {
+		case trimmed == PageBreak:
+			lines = append(lines, strings.Trim(delimiter, "\n"))
+		case strings.HasPrefix(trimmed, "//"):
+			// Comments are not presented
+		case reSource.MatchString(trimmed):
+			language = reSource.FindStringSubmatch(trimmed)[1]
+		case trimmed == "----" || trimmed == "....":
+			lines = append(lines, "```"+language)
+			fence = trimmed
+			language = ""
+		case reHeading.MatchString(trimmed):
+			match := reHeading.FindStringSubmatch(trimmed)
+			lines = append(lines, strings.Repeat("#", len(match[1]))+" "+inline(match[2]))
+		case reOrdered.MatchString(trimmed):
+			match := reOrdered.FindStringSubmatch(trimmed)
+			lines = append(lines, strings.Repeat("  ", len(match[1])-1)+"1. "+inline(match[2]))
+		case reUnordered.MatchString(trimmed):
+			match := reUnordered.FindStringSubmatch(trimmed)
+			lines = append(lines, strings.Repeat("  ", len(match[1])-1)+"- "+inline(match[2]))
+		default:
+			lines = append(lines, inline(line))
+		}
+	}
+
+	md := strings.Join(lines, "\n")
+	if len(header) > 0 {
+		md = strings.TrimPrefix(delimiter, "\n") + strings.Join(header, "\n") + delimiter + md
+	}
+	return md
+}
+
+// inline converts the inline formatting of a line of AsciiDoc
+func inline(line string) string {
+	line = reLink.ReplaceAllString(line, "[$2]($1)")
+	line = reBold.ReplaceAllString(line, "$1**$2**$3")
+	line = reItalic.ReplaceAllString(line, "$1*$2*$3")
+	return line
+}
@@ -0,0 +1,69 @@
+package asciidoc_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/asciidoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAsciiDoc(t *testing.T) {
+	assert.True(t, asciidoc.IsAsciiDoc("slides.adoc"))
+	assert.True(t, asciidoc.IsAsciiDoc("slides.AsciiDoc"))
+	assert.False(t, asciidoc.IsAsciiDoc("slides.md"))
+}
+
+func TestToMarkdown(t *testing.T) {
+	doc := `= Welcome
+:author: Gopher
+:toc:
+
+A *terminal* based _presentation_ tool, see https://example.com[the docs].
+
+<<<
+
+== Code
+
+// a comment
+[source,go]
+----
+fmt.Println("*not bold*")
+----
+
+* One
+** Nested
+. First
+
+<<<
+
+....
+literal
+....`
+
+	want := `---
+author: "Gopher"
+---
+# Welcome
+
+A **terminal** based *presentation* tool, see [the docs](https://example.com).
+
+---
+
+## Code
+
+` + "```go" + `
+fmt.Println("*not bold*")
+` + "```" + `
+
+- One
+  - Nested
+1. First
+
+---
+
+` + "```" + `
+literal
+` + "```"
+
+	assert.Equal(t, want, asciidoc.ToMarkdown(doc, "\n---\n"))
+}
@@ -0,0 +1,139 @@
+// Package badge implements rendering <!-- badge: label message color -->
+// directives as small colored badges, like the shields of a README
+package badge
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maaslalani/slides/internal/directive"
+	"github.com/maaslalani/slides/internal/placeholder"
+)
+
+var reHex = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}){1,2}$`)
+
+var colors = map[string]lipgloss.Color{
+	"green":  lipgloss.Color("#4C9A2A"),
+	"red":    lipgloss.Color("#C0392B"),
+	"yellow": lipgloss.Color("#B7950B"),
+	"orange": lipgloss.Color("#D35400"),
+	"blue":   lipgloss.Color("#2874A6"),
+	"purple": lipgloss.Color("#7D3C98"),
+	"gray":   lipgloss.Color("#616A6B"),
+	"grey":   lipgloss.Color("#616A6B"),
+}
+
+var (
+	labelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Background(colors["gray"]).Padding(0, 1)
+	valueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Padding(0, 1)
+)
+
+// Badge is a label and message displayed with a color
+type Badge struct {
+	Label   string
+	Message string
+	Color   lipgloss.Color
+}
+
+// Parse parses a badge written as "label message color", the message can
+// span multiple words. The color is either one of the named colors or a hex
+// color.
+func Parse(spec string) (Badge, bool) {
+	fields := strings.Fields(spec)
+	if len(fields) < 3 {
+		return Badge{}, false
+	}
+	last := strings.ToLower(fields[len(fields)-1])
+	color, ok := colors[last]
+	if !ok {
+		if !reHex.MatchString(last) {
+			return Badge{}, false
+		}
+		color = lipgloss.Color(last)
+	}
+	return Badge{
+		Label:   fields[0],
+		Message: strings.Join(fields[1:len(fields)-1], " "),
+		Color:   color,
+	}, true
+}
+
+// Render renders the badge
+func (b Badge) Render() string {
+	return labelStyle.Render(b.Label) + valueStyle.Copy().Background(b.Color).Render(b.Message)
+}
+
+// Extract replaces the lines of the markdown made up of valid badges, outside
+// of code blocks, with placeholders and returns the badges of each line.
+// Badges which are invalid or written alongside other text are replaced with
+// their plain text.
+func Extract(markdown string) (string, [][]Badge) {
+	var (
+		lines  = strings.Split(markdown, "\n")
+		badges [][]Badge
+		fence  bool
+	)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = !fence
+			continue
+		}
+		if fence || !strings.Contains(line, "badge") {
+			continue
+		}
+
+		var (
+			row   []Badge
+			valid = true
+			rest  = line
+		)
+		for _, m := range directive.Matches(line) {
+			if m.Name != "badge" {
+				continue
+			}
+			b, ok := Parse(m.Value)
+			if !ok {
+				valid = false
+			}
+			row = append(row, b)
+			rest = strings.Replace(rest, m.Raw, "", 1)
+		}
+		if row == nil {
+			continue
+		}
+		if valid && strings.TrimSpace(rest) == "" {
+			lines[i] = placeholder.Badge.Mark(len(badges))
+			badges = append(badges, row)
+			continue
+		}
+		for _, m := range directive.Matches(line) {
+			if m.Name == "badge" {
+				line = strings.Replace(line, m.Raw, "`"+m.Value+"`", 1)
+			}
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n"), badges
+}
+
+// Restore replaces the lines of the rendered slide containing placeholders
+// with their badges laid out horizontally, keeping the indentation of the
+// line
+func Restore(rendered string, badges [][]Badge) string {
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		n, col, ok := placeholder.Badge.Find(line)
+		if !ok || n >= len(badges) {
+			continue
+		}
+		rendered := make([]string, len(badges[n]))
+		for j, b := range badges[n] {
+			rendered[j] = b.Render()
+		}
+		indent := strings.Repeat(" ", col)
+		lines[i] = indent + strings.Join(rendered, " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,53 @@
+package badge_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maaslalani/slides/internal/badge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec string
+		want badge.Badge
+		ok   bool
+	}{
+		{spec: "build passing green", want: badge.Badge{Label: "build", Message: "passing", Color: lipgloss.Color("#4C9A2A")}, ok: true},
+		{spec: "coverage 98 % #ff00ff", want: badge.Badge{Label: "coverage", Message: "98 %", Color: lipgloss.Color("#ff00ff")}, ok: true},
+		{spec: "build passing", ok: false},
+		{spec: "build passing sparkly", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, ok := badge.Parse(tt.spec)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	markdown := "# Status\n<!-- badge: build passing green --> <!-- badge: license MIT blue -->\nText <!-- badge: version 1.0 red -->\n<!-- badge: broken -->"
+
+	got, badges := badge.Extract(markdown)
+
+	assert.Len(t, badges, 1)
+	assert.Len(t, badges[0], 2)
+	assert.Equal(t, "# Status\n\uE0040\uE005\nText `version 1.0 red`\n`broken`", got)
+}
+
+func TestRestore(t *testing.T) {
+	_, badges := badge.Extract("<!-- badge: build passing green --> <!-- badge: license MIT blue -->")
+
+	got := badge.Restore("  \uE0040\uE005  ", badges)
+
+	assert.True(t, strings.HasPrefix(got, "  "))
+	assert.Contains(t, got, "build")
+	assert.Contains(t, got, "passing")
+	assert.Contains(t, got, "MIT")
+	assert.NotContains(t, got, "\uE004")
+}
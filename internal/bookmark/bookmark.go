@@ -0,0 +1,109 @@
+// Package bookmark implements marking slides to jump back to, and storing the
+// bookmarks of a deck in a file next to it
+package bookmark
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Set is the pages of the bookmarked slides, in order
+type Set []int
+
+// Has returns whether the page is bookmarked
+func (s Set) Has(page int) bool {
+	i := sort.SearchInts(s, page)
+	return i < len(s) && s[i] == page
+}
+
+// Toggle bookmarks the page, or removes its bookmark if it is bookmarked
+func (s Set) Toggle(page int) Set {
+	i := sort.SearchInts(s, page)
+	if i < len(s) && s[i] == page {
+		return append(s[:i:i], s[i+1:]...)
+	}
+	toggled := append(s[:i:i], page)
+	return append(toggled, s[i:]...)
+}
+
+// Next returns the first bookmark after the page, wrapping around to the
+// first bookmark, and whether there are any bookmarks
+func (s Set) Next(page int) (int, bool) {
+	if len(s) == 0 {
+		return 0, false
+	}
+	for _, p := range s {
+		if p > page {
+			return p, true
+		}
+	}
+	return s[0], true
+}
+
+// Previous returns the last bookmark before the page, wrapping around to the
+// last bookmark, and whether there are any bookmarks
+func (s Set) Previous(page int) (int, bool) {
+	if len(s) == 0 {
+		return 0, false
+	}
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < page {
+			return s[i], true
+		}
+	}
+	return s[len(s)-1], true
+}
+
+// Within returns the bookmarks of the pages of a deck of total slides,
+// leaving out the slides which no longer exist
+func (s Set) Within(total int) Set {
+	within := Set{}
+	for _, p := range s {
+		if p >= 0 && p < total {
+			within = append(within, p)
+		}
+	}
+	return within
+}
+
+// File returns the file the bookmarks of the deck are stored in, a hidden
+// file next to the deck
+func File(deck string) string {
+	deck = filepath.Clean(deck)
+	return filepath.Join(filepath.Dir(deck), "."+filepath.Base(deck)+".bookmarks")
+}
+
+// Load returns the bookmarks stored in the file, a missing or invalid file
+// has no bookmarks. Slides are numbered from 1 in the file.
+func Load(file string) Set {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Set{}
+	}
+	var numbers []int
+	if err := json.Unmarshal(data, &numbers); err != nil {
+		return Set{}
+	}
+	s := Set{}
+	for _, n := range numbers {
+		if !s.Has(n - 1) {
+			s = s.Toggle(n - 1)
+		}
+	}
+	return s
+}
+
+// Save stores the bookmarks in the file
+func Save(file string, s Set) error {
+	numbers := make([]int, len(s))
+	for i, p := range s {
+		numbers[i] = p + 1
+	}
+	data, err := json.Marshal(numbers)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
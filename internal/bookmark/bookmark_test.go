@@ -0,0 +1,71 @@
+package bookmark_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maaslalani/slides/internal/bookmark"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToggle(t *testing.T) {
+	s := bookmark.Set{}.Toggle(5).Toggle(1).Toggle(3)
+	assert.Equal(t, bookmark.Set{1, 3, 5}, s)
+	assert.True(t, s.Has(3))
+
+	s = s.Toggle(3)
+	assert.Equal(t, bookmark.Set{1, 5}, s)
+	assert.False(t, s.Has(3))
+}
+
+func TestNextPrevious(t *testing.T) {
+	s := bookmark.Set{1, 3, 5}
+
+	tests := []struct {
+		page     int
+		next     int
+		previous int
+	}{
+		{page: 0, next: 1, previous: 5},
+		{page: 1, next: 3, previous: 5},
+		{page: 4, next: 5, previous: 3},
+		{page: 5, next: 1, previous: 3},
+	}
+	for _, tt := range tests {
+		next, ok := s.Next(tt.page)
+		assert.True(t, ok)
+		assert.Equal(t, tt.next, next)
+		previous, ok := s.Previous(tt.page)
+		assert.True(t, ok)
+		assert.Equal(t, tt.previous, previous)
+	}
+
+	_, ok := bookmark.Set{}.Next(0)
+	assert.False(t, ok)
+	_, ok = bookmark.Set{}.Previous(0)
+	assert.False(t, ok)
+}
+
+func TestWithin(t *testing.T) {
+	assert.Equal(t, bookmark.Set{1, 3}, bookmark.Set{1, 3, 5}.Within(4))
+}
+
+func TestFile(t *testing.T) {
+	assert.Equal(t, filepath.Join("talks", ".intro.md.bookmarks"), bookmark.File(filepath.Join("talks", "intro.md")))
+	assert.Equal(t, filepath.Join("talks", ".intro.bookmarks"), bookmark.File(filepath.Join("talks", "intro")+string(filepath.Separator)))
+}
+
+func TestSaveLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".talk.md.bookmarks")
+	assert.Equal(t, bookmark.Set{}, bookmark.Load(file))
+
+	assert.NoError(t, bookmark.Save(file, bookmark.Set{0, 4}))
+	data, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "[1,5]\n", string(data))
+	assert.Equal(t, bookmark.Set{0, 4}, bookmark.Load(file))
+
+	assert.NoError(t, os.WriteFile(file, []byte("not json"), 0o644))
+	assert.Equal(t, bookmark.Set{}, bookmark.Load(file))
+}
@@ -0,0 +1,72 @@
+// Package capability implements detection of terminal capabilities and
+// conditional slide content based on them
+package capability
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/maaslalani/slides/internal/graphics"
+	"github.com/muesli/termenv"
+)
+
+// Supported capabilities
+const (
+	TrueColor = "truecolor"
+	Images    = "images"
+	Unicode   = "unicode"
+)
+
+// Set contains the capabilities supported by a terminal
+type Set map[string]bool
+
+// Detect returns the capabilities of the current terminal
+func Detect() Set {
+	return Set{
+		TrueColor: termenv.ColorProfile() == termenv.TrueColor,
+		Images:    graphics.Detect() != graphics.None,
+		Unicode:   isUnicode(os.Getenv),
+	}
+}
+
+func isUnicode(getenv func(string) string) bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := getenv(key); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return false
+}
+
+// Has returns whether all of the comma separated capabilities are supported
+func (s Set) Has(caps string) bool {
+	for _, c := range strings.Split(caps, ",") {
+		if !s[strings.ToLower(strings.TrimSpace(c))] {
+			return false
+		}
+	}
+	return true
+}
+
+// ?: means non-capture group
+var re = regexp.MustCompile(`(?s)<!--\s*if-caps\s*:\s*(.*?)\s*-->\n?(.*?)(?:<!--\s*else\s*-->\n?(.*?))?<!--\s*end-caps\s*-->\n?`)
+
+// Apply resolves the conditional blocks of the slide, keeping the content
+// of the branch matching the capabilities:
+//
+// <!-- if-caps: images -->
+// shown in terminals which can display images
+// <!-- else -->
+// shown everywhere else
+// <!-- end-caps -->
+func Apply(slide string, s Set) string {
+	return re.ReplaceAllStringFunc(slide, func(block string) string {
+		match := re.FindStringSubmatch(block)
+		if s.Has(match[1]) {
+			return match[2]
+		}
+		return match[3]
+	})
+}
@@ -0,0 +1,47 @@
+package capability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsUnicode(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{env: map[string]string{"LANG": "en_US.UTF-8"}, want: true},
+		{env: map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"}, want: false},
+		{env: map[string]string{"LC_CTYPE": "en_US.utf8"}, want: true},
+		{env: map[string]string{}, want: false},
+	}
+	for _, tt := range tests {
+		got := isUnicode(func(key string) string { return tt.env[key] })
+		assert.Equal(t, tt.want, got, tt.env)
+	}
+}
+
+func TestHas(t *testing.T) {
+	s := Set{Images: true, Unicode: true}
+	assert.True(t, s.Has("images"))
+	assert.True(t, s.Has("images, Unicode"))
+	assert.False(t, s.Has("images,truecolor"))
+	assert.False(t, s.Has("unknown"))
+}
+
+func TestApply(t *testing.T) {
+	slide := `# Diagram
+<!-- if-caps: images -->
+![diagram](diagram.png)
+<!-- else -->
+[ A ] -> [ B ]
+<!-- end-caps -->
+<!-- if-caps: truecolor -->
+gradient
+<!-- end-caps -->
+Done`
+
+	assert.Equal(t, "# Diagram\n![diagram](diagram.png)\ngradient\nDone", Apply(slide, Set{Images: true, TrueColor: true}))
+	assert.Equal(t, "# Diagram\n[ A ] -> [ B ]\nDone", Apply(slide, Set{}))
+}
@@ -1,12 +1,16 @@
 package code
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,6 +18,9 @@ import (
 type Block struct {
 	Code     string
 	Language string
+	// Highlight are the one based lines given with {hl=3-5} after the
+	// language, which are highlighted when the block is rendered
+	Highlight []int
 }
 
 type Result struct {
@@ -23,7 +30,11 @@ type Result struct {
 }
 
 // ?: means non-capture group
-var re = regexp.MustCompile("(?s)(?:```|~~~)(\\w+)\n(.*?)\n(?:```|~~~)\\s?")
+var re = regexp.MustCompile("(?s)(?:```|~~~)(\\w+)([ \t][^\n]*)?\n(.*?)\n(?:```|~~~)\\s?")
+
+// reHighlight matches the lines to highlight in the info string of a code
+// block, such as {hl=3-5} or {hl=1,4-6}
+var reHighlight = regexp.MustCompile(`\{\s*hl\s*=\s*([0-9,\- ]+)\}`)
 
 var (
 	ErrParse = errors.New("Error: could not parse code block")
@@ -38,12 +49,13 @@ func Parse(markdown string) ([]Block, error) {
 	for _, match := range matches {
 		// There was either no language specified or no code block
 		// Either way, we cannot execute the expression
-		if len(match) < 3 {
+		if len(match) < 4 {
 			continue
 		}
 		rv = append(rv, Block{
-			Language: match[1],
-			Code:     match[2],
+			Language:  match[1],
+			Code:      match[3],
+			Highlight: Highlighted(match[2]),
 		})
 
 	}
@@ -55,16 +67,116 @@ func Parse(markdown string) ([]Block, error) {
 	return rv, nil
 }
 
+// Highlighted returns the one based lines to highlight, in order, given in
+// the info string of a code block such as "go {hl=3-5}"
+func Highlighted(info string) []int {
+	match := reHighlight.FindStringSubmatch(info)
+	if match == nil {
+		return nil
+	}
+	seen := map[int]bool{}
+	var lines []int
+	for _, spec := range strings.Split(match[1], ",") {
+		bounds := strings.SplitN(strings.TrimSpace(spec), "-", 2)
+		first, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil || first < 1 {
+			continue
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil || last < first {
+				continue
+			}
+		}
+		for line := first; line <= last; line++ {
+			if !seen[line] {
+				seen[line] = true
+				lines = append(lines, line)
+			}
+		}
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// Unterminated returns whether a code block of the markdown is never closed
+func Unterminated(markdown string) bool {
+	var fence string
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+		case fence != "" && strings.HasPrefix(trimmed, fence):
+			fence = ""
+		}
+	}
+	return fence != ""
+}
+
 const (
 	// ExitCodeInternalError represents the exit code in which the code
 	// executing the code didn't work.
 	ExitCodeInternalError = -1
+	// ExitCodeTimeout represents the exit code of code which was stopped
+	// because it ran for longer than the timeout.
+	ExitCodeTimeout = -2
 )
 
+// Options customizes the execution of code blocks
+type Options struct {
+	// Runners adds languages to the built-in languages, or overrides them
+	Runners map[string]Language
+	// Timeout stops the execution of code which runs for longer, zero means
+	// the code may run forever
+	Timeout time.Duration
+	// Dir is the working directory the code runs in, the current directory
+	// is used if it is empty
+	Dir string
+	// Env are environment variables, written as NAME=value, which are set
+	// in addition to the environment of slides
+	Env []string
+}
+
+// Language returns how code in the language is run, and whether the
+// language is supported
+func (o Options) Language(name string) (Language, bool) {
+	if language, ok := o.Runners[name]; ok {
+		return language, true
+	}
+	language, ok := Languages[name]
+	return language, ok
+}
+
+// Runner returns a language running code with the command, %s in the
+// command is replaced with the file containing the code. The file is
+// appended to commands without a placeholder.
+func Runner(extension, command string) Language {
+	args := strings.Fields(command)
+	placeholder := false
+	for i, arg := range args {
+		if strings.Contains(arg, "%s") || strings.Contains(arg, "<file>") {
+			args[i] = strings.ReplaceAll(arg, "%s", "<file>")
+			placeholder = true
+		}
+	}
+	if !placeholder {
+		args = append(args, "<file>")
+	}
+	return Language{Extension: extension, Commands: cmds{args}}
+}
+
 // Execute takes a code.Block and returns the output of the executed code
 func Execute(code Block) Result {
+	return ExecuteWith(code, Options{})
+}
+
+// ExecuteWith executes the code.Block with the options and returns the output
+// of the executed code
+func ExecuteWith(code Block, opts Options) Result {
 	// Check supported language
-	language, ok := Languages[code.Language]
+	language, ok := opts.Language(code.Language)
 	if !ok {
 		return Result{
 			Out:      "Error: unsupported language",
@@ -72,8 +184,23 @@ func Execute(code Block) Result {
 		}
 	}
 
+	// Check the commands are installed before running any of them, so that a
+	// missing tool isn't mistaken for code which failed. Commands with
+	// placeholders run files created along the way, such as compiled code.
+	for _, c := range language.Commands {
+		if strings.Contains(c[0], "<") {
+			continue
+		}
+		if _, err := exec.LookPath(c[0]); err != nil {
+			return Result{
+				Out:      fmt.Sprintf("Error: %s not found in PATH", c[0]),
+				ExitCode: ExitCodeInternalError,
+			}
+		}
+	}
+
 	// Write the code block to a temporary file
-	f, err := ioutil.TempFile(os.TempDir(), "slides-*."+Languages[code.Language].Extension)
+	f, err := ioutil.TempFile(os.TempDir(), "slides-*."+language.Extension)
 	if err != nil {
 		return Result{
 			Out:      "Error: could not create file",
@@ -105,6 +232,13 @@ func Execute(code Block) Result {
 		"<path>", filepath.Dir(f.Name()),
 	)
 
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
 	// For accuracy of program execution speed, we can't put anything after
 	// recording the start time or before recording the end time.
 	start := time.Now()
@@ -116,8 +250,18 @@ func Execute(code Block) Result {
 			command = append(command, repl.Replace(v))
 		}
 		// execute and write output
-		cmd := exec.Command(command[0], command[1:]...)
-		out, err := cmd.Output()
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Dir = opts.Dir
+		if len(opts.Env) > 0 {
+			cmd.Env = append(os.Environ(), opts.Env...)
+		}
+		out, err := runOutput(cmd)
+		if ctx.Err() == context.DeadlineExceeded {
+			output.Write(out)
+			output.WriteString(fmt.Sprintf("Error: timed out after %s", opts.Timeout))
+			exitCode = ExitCodeTimeout
+			break
+		}
 		if err != nil {
 			output.Write([]byte(err.Error()))
 		} else {
@@ -142,3 +286,24 @@ func Execute(code Block) Result {
 		ExecutionTime: end.Sub(start),
 	}
 }
+
+// runOutput runs the command and returns its standard output. The output is
+// written to a file rather than a pipe, so that waiting for the command
+// doesn't wait for the processes it started which still hold on to the
+// pipe, such as the children of a command stopped by the timeout.
+func runOutput(cmd *exec.Cmd) ([]byte, error) {
+	f, err := ioutil.TempFile(os.TempDir(), "slides-output-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	cmd.Stdout = f
+	err = cmd.Run()
+	out, readErr := ioutil.ReadFile(f.Name())
+	if err == nil {
+		err = readErr
+	}
+	return out, err
+}
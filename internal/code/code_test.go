@@ -1,6 +1,7 @@
 package code_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/maaslalani/slides/internal/code"
@@ -112,6 +113,21 @@ fmt.Println("Hello, world!")
 				},
 			},
 		},
+		{
+			markdown: `
+~~~go {hl=2}
+fmt.Println("Hello")
+fmt.Println("world!")
+~~~
+`,
+			expected: []code.Block{
+				{
+					Code:      "fmt.Println(\"Hello\")\nfmt.Println(\"world!\")",
+					Language:  "go",
+					Highlight: []int{2},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tt {
@@ -129,6 +145,47 @@ fmt.Println("Hello, world!")
 			if block.Language != expected.Language {
 				t.Fatalf("incorrect language, got %s, want %s", block.Language, expected.Language)
 			}
+			if !reflect.DeepEqual(block.Highlight, expected.Highlight) {
+				t.Fatalf("incorrect highlight, got %v, want %v", block.Highlight, expected.Highlight)
+			}
+		}
+	}
+}
+
+func TestHighlighted(t *testing.T) {
+	tt := []struct {
+		info     string
+		expected []int
+	}{
+		{info: "go {hl=3-5}", expected: []int{3, 4, 5}},
+		{info: "go {hl=6,1-2}", expected: []int{1, 2, 6}},
+		{info: "go { hl = 2, 2-3 }", expected: []int{2, 3}},
+		{info: "go {hl=0,5-4}", expected: nil},
+		{info: "go", expected: nil},
+	}
+
+	for _, tc := range tt {
+		got := code.Highlighted(tc.info)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Highlighted(%q) = %v, want %v", tc.info, got, tc.expected)
+		}
+	}
+}
+
+func TestUnterminated(t *testing.T) {
+	tt := []struct {
+		markdown string
+		expected bool
+	}{
+		{markdown: "# Title", expected: false},
+		{markdown: "~~~go\nfmt.Println()\n~~~", expected: false},
+		{markdown: "~~~go\nfmt.Println()", expected: true},
+		{markdown: "~~~md\n```\n~~~", expected: false},
+		{markdown: "~~~\n~~~\n~~~bash", expected: true},
+	}
+	for _, tc := range tt {
+		if got := code.Unterminated(tc.markdown); got != tc.expected {
+			t.Errorf("Unterminated(%q) = %v, want %v", tc.markdown, got, tc.expected)
 		}
 	}
 }
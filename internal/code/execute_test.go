@@ -1,7 +1,9 @@
 package code_test
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/maaslalani/slides/internal/code"
 )
@@ -73,3 +75,74 @@ func main() {
 		}
 	}
 }
+
+func TestExecute_missingCommand(t *testing.T) {
+	code.Languages["missing"] = code.Language{
+		Extension: "txt",
+		Commands:  [][]string{{"slides-missing-command", "<file>"}},
+	}
+	defer delete(code.Languages, "missing")
+
+	r := code.Execute(code.Block{Code: "hello", Language: "missing"})
+	if r.Out != "Error: slides-missing-command not found in PATH" {
+		t.Fatalf("unexpected output, got %s", r.Out)
+	}
+	if r.ExitCode != code.ExitCodeInternalError {
+		t.Fatalf("unexpected exit code, got %d, want %d", r.ExitCode, code.ExitCodeInternalError)
+	}
+}
+
+func TestExecuteWith_runner(t *testing.T) {
+	opts := code.Options{
+		Runners: map[string]code.Language{
+			"shell": code.Runner("sh", "sh %s"),
+		},
+	}
+	r := code.ExecuteWith(code.Block{Code: `echo "Hello, runner!"`, Language: "shell"}, opts)
+	if r.Out != "Hello, runner!\n" {
+		t.Fatalf("unexpected output, got %s", r.Out)
+	}
+	if r.ExitCode != 0 {
+		t.Fatalf("unexpected exit code, got %d, want 0", r.ExitCode)
+	}
+}
+
+func TestExecuteWith_timeout(t *testing.T) {
+	opts := code.Options{Timeout: 100 * time.Millisecond}
+	r := code.ExecuteWith(code.Block{Code: "sleep 5", Language: "bash"}, opts)
+	if r.Out != "Error: timed out after 100ms" {
+		t.Fatalf("unexpected output, got %s", r.Out)
+	}
+	if r.ExitCode != code.ExitCodeTimeout {
+		t.Fatalf("unexpected exit code, got %d, want %d", r.ExitCode, code.ExitCodeTimeout)
+	}
+	if r.ExecutionTime > time.Second {
+		t.Fatalf("code ran for %s after the timeout", r.ExecutionTime)
+	}
+}
+
+func TestExecuteWith_dirAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	opts := code.Options{Dir: dir, Env: []string{"GREETING=hello"}}
+	r := code.ExecuteWith(code.Block{Code: `echo "$GREETING from $(pwd)"`, Language: "bash"}, opts)
+	if want := "hello from " + dir + "\n"; r.Out != want {
+		t.Fatalf("unexpected output, got %q, want %q", r.Out, want)
+	}
+}
+
+func TestRunner(t *testing.T) {
+	tests := []struct {
+		command string
+		want    [][]string
+	}{
+		{command: "deno run %s", want: [][]string{{"deno", "run", "<file>"}}},
+		{command: "deno run", want: [][]string{{"deno", "run", "<file>"}}},
+		{command: "tool --input=%s", want: [][]string{{"tool", "--input=<file>"}}},
+	}
+	for _, tt := range tests {
+		got := code.Runner("ts", tt.command)
+		if got.Extension != "ts" || !reflect.DeepEqual([][]string(got.Commands), tt.want) {
+			t.Fatalf("unexpected runner for %q, got %+v", tt.command, got)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package code
+
+import "strings"
+
+// ExpandTabs replaces the tabs inside of the fenced code blocks of the given
+// markdown with spaces, aligned to tab stops every width columns. Content
+// outside of code blocks is left untouched.
+func ExpandTabs(markdown string, width int) string {
+	if width <= 0 || !strings.Contains(markdown, "\t") {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	var fence string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" {
+			if strings.HasPrefix(trimmed, "```") {
+				fence = "```"
+			} else if strings.HasPrefix(trimmed, "~~~") {
+				fence = "~~~"
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) {
+			fence = ""
+			continue
+		}
+		lines[i] = expandLine(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func expandLine(line string, width int) string {
+	var b strings.Builder
+	column := 0
+	for _, r := range line {
+		if r == '\t' {
+			spaces := width - column%width
+			b.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+			continue
+		}
+		b.WriteRune(r)
+		column++
+	}
+	return b.String()
+}
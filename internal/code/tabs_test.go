@@ -0,0 +1,44 @@
+package code_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/code"
+)
+
+func TestExpandTabs(t *testing.T) {
+	tt := []struct {
+		markdown string
+		width    int
+		expected string
+	}{
+		{
+			markdown: "~~~go\nfunc main() {\n\tfmt.Println()\n}\n~~~",
+			width:    4,
+			expected: "~~~go\nfunc main() {\n    fmt.Println()\n}\n~~~",
+		},
+		{
+			markdown: "```go\na\tb\n\t\tc\n```",
+			width:    4,
+			expected: "```go\na   b\n        c\n```",
+		},
+		{
+			// Tabs outside of code blocks are left alone
+			markdown: "a\tb\n~~~\n\tc\n~~~\nd\te",
+			width:    2,
+			expected: "a\tb\n~~~\n  c\n~~~\nd\te",
+		},
+		{
+			markdown: "~~~go\n\tfmt.Println()\n~~~",
+			width:    0,
+			expected: "~~~go\n\tfmt.Println()\n~~~",
+		},
+	}
+
+	for _, tc := range tt {
+		got := code.ExpandTabs(tc.markdown, tc.width)
+		if got != tc.expected {
+			t.Fatalf("incorrect tab expansion, got %q, want %q", got, tc.expected)
+		}
+	}
+}
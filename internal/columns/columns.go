@@ -0,0 +1,41 @@
+// Package columns implements splitting slides into columns which are
+// rendered side by side
+package columns
+
+import "strings"
+
+// Separator is the line separating the columns of a slide
+const Separator = ":::"
+
+// MinWidth is the narrowest a column is rendered at, slides with narrower
+// columns are stacked instead
+const MinWidth = 30
+
+// Split returns the columns of the markdown, which are separated by lines
+// only containing the Separator outside of code blocks. Markdown without a
+// separator is a single column.
+func Split(markdown string) []string {
+	var (
+		columns []string
+		column  []string
+		fence   bool
+	)
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = !fence
+		}
+		if !fence && trimmed == Separator {
+			columns = append(columns, strings.Join(column, "\n"))
+			column = nil
+			continue
+		}
+		column = append(column, line)
+	}
+	return append(columns, strings.Join(column, "\n"))
+}
+
+// Fit returns whether the columns fit side by side in the width
+func Fit(columns []string, width int) bool {
+	return len(columns) > 1 && width/len(columns) >= MinWidth
+}
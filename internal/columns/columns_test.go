@@ -0,0 +1,53 @@
+package columns_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/columns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string
+	}{
+		{
+			name:     "Single column",
+			markdown: "# Title\n\nText",
+			want:     []string{"# Title\n\nText"},
+		},
+		{
+			name:     "Two columns",
+			markdown: "# Before\n:::\n# After",
+			want:     []string{"# Before", "# After"},
+		},
+		{
+			name:     "Three columns",
+			markdown: "One\n  :::  \nTwo\n:::\nThree",
+			want:     []string{"One", "Two", "Three"},
+		},
+		{
+			name:     "Separators in code blocks are ignored",
+			markdown: "Text\n```\n:::\n```",
+			want:     []string{"Text\n```\n:::\n```"},
+		},
+		{
+			name:     "Separators must be on a line of their own",
+			markdown: "Text ::: more",
+			want:     []string{"Text ::: more"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, columns.Split(tt.markdown))
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	assert.False(t, columns.Fit([]string{"One"}, 100))
+	assert.True(t, columns.Fit([]string{"One", "Two"}, 100))
+	assert.False(t, columns.Fit([]string{"One", "Two"}, 50))
+}
@@ -0,0 +1,43 @@
+// Package contact implements generating a closing slide with the contact
+// information of the presenter
+package contact
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Info is the contact information of the presenter
+type Info struct {
+	Author  string
+	Contact string
+	Email   string
+	Social  string
+}
+
+// Empty returns whether there is no contact information, the author alone
+// isn't worth a slide of its own
+func (i Info) Empty() bool {
+	return i.Contact == "" && i.Email == "" && i.Social == ""
+}
+
+// Slide returns the markdown of a closing slide listing the contact
+// information
+func Slide(i Info) string {
+	var b strings.Builder
+	b.WriteString("# Thank you!\n")
+	if i.Author != "" {
+		fmt.Fprintf(&b, "\n**%s**\n", i.Author)
+	}
+	b.WriteString("\n")
+	for _, field := range []struct{ name, value string }{
+		{"Contact", i.Contact},
+		{"Email", i.Email},
+		{"Social", i.Social},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(&b, "* %s: %s\n", field.name, field.value)
+		}
+	}
+	return b.String()
+}
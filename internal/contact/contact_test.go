@@ -0,0 +1,24 @@
+package contact_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/contact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlide(t *testing.T) {
+	info := contact.Info{
+		Author: "Gopher",
+		Email:  "gopher@example.com",
+		Social: "@gopher",
+	}
+
+	want := "# Thank you!\n\n**Gopher**\n\n* Email: gopher@example.com\n* Social: @gopher\n"
+	assert.Equal(t, want, contact.Slide(info))
+}
+
+func TestEmpty(t *testing.T) {
+	assert.True(t, contact.Info{Author: "Gopher"}.Empty())
+	assert.False(t, contact.Info{Contact: "gopher.dev"}.Empty())
+}
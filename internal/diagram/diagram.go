@@ -0,0 +1,567 @@
+// Package diagram implements drawing the flowcharts of mermaid and dot code
+// blocks with box-drawing characters, so that diagrams kept as source are
+// presented as diagrams
+package diagram
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Languages are the languages of the code blocks which are drawn
+var Languages = map[string]func(source string) (Graph, error){
+	"mermaid":  ParseMermaid,
+	"dot":      ParseDot,
+	"graphviz": ParseDot,
+}
+
+// Graph is a flowchart of nodes connected by edges
+type Graph struct {
+	// Nodes are the IDs of the nodes in the order they were declared
+	Nodes []string
+	// Labels are the texts of the nodes, a node without a label shows its
+	// ID
+	Labels map[string]string
+	// Rounded are the nodes drawn with rounded corners
+	Rounded map[string]bool
+	Edges   []Edge
+	// Horizontal lays the flowchart out from left to right instead of from
+	// top to bottom
+	Horizontal bool
+}
+
+// Edge is an arrow from one node to another
+type Edge struct {
+	From, To string
+	Label    string
+}
+
+// ErrCycle is returned for flowcharts which loop back on themselves, which
+// can't be laid out
+var ErrCycle = errors.New("flowcharts with cycles are not supported")
+
+func newGraph() Graph {
+	return Graph{Labels: map[string]string{}, Rounded: map[string]bool{}}
+}
+
+// add declares the node unless it is already declared, setting its label if
+// it is given one
+func (g *Graph) add(id, label string, rounded bool) {
+	if _, ok := g.Labels[id]; !ok {
+		g.Nodes = append(g.Nodes, id)
+		g.Labels[id] = id
+	}
+	if label != "" {
+		g.Labels[id] = label
+		g.Rounded[id] = rounded
+	}
+}
+
+// Transform replaces the mermaid and dot code blocks of the markdown with
+// code blocks of their drawings. Code blocks which can't be drawn are left as
+// they are, followed by a note saying why.
+func Transform(markdown string) string {
+	var (
+		out    []string
+		body   []string
+		fence  string
+		parse  func(string) (Graph, error)
+		opened string
+	)
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+			fields := strings.Fields(strings.TrimLeft(trimmed, fence[:1]))
+			if len(fields) > 0 {
+				parse = Languages[fields[0]]
+			}
+			if parse != nil {
+				opened = line
+				body = nil
+				continue
+			}
+		case fence != "" && strings.HasPrefix(trimmed, fence):
+			fence = ""
+			if parse != nil {
+				source := strings.Join(body, "\n")
+				drawing, err := draw(parse, source)
+				if err != nil {
+					out = append(out, opened)
+					out = append(out, body...)
+					out = append(out, line, "", "> Diagram not drawn: "+err.Error())
+				} else {
+					out = append(out, "```", drawing, "```")
+				}
+				parse = nil
+				continue
+			}
+		case parse != nil:
+			body = append(body, line)
+			continue
+		}
+		out = append(out, line)
+	}
+	if parse != nil {
+		// A code block which is never closed is left as it is
+		out = append(out, opened)
+		out = append(out, body...)
+	}
+	return strings.Join(out, "\n")
+}
+
+func draw(parse func(string) (Graph, error), source string) (string, error) {
+	g, err := parse(source)
+	if err != nil {
+		return "", err
+	}
+	if len(g.Nodes) == 0 {
+		return "", errors.New("the diagram has no nodes")
+	}
+	return Draw(g)
+}
+
+// node is a node of the flowchart placed in its layer, dummy nodes carry the
+// edges which cross layers
+type node struct {
+	id      string
+	label   string
+	rounded bool
+	dummy   bool
+	// x and y are the top left corner of the node, w and h its size
+	x, y, w, h int
+	// span is the width of the column of the node, when the layers are
+	// columns
+	span int
+}
+
+// link connects two placed nodes of adjacent layers
+type link struct {
+	from, to *node
+	label    string
+}
+
+// Draw draws the flowchart, layering the nodes so that every edge points to
+// a later layer
+func Draw(g Graph) (string, error) {
+	layers, links, err := layout(g)
+	if err != nil {
+		return "", err
+	}
+	if g.Horizontal {
+		return drawHorizontal(layers, links), nil
+	}
+	return drawVertical(layers, links), nil
+}
+
+// layout places the nodes in layers by the longest path leading to them,
+// breaking edges which span several layers into links through dummy nodes
+func layout(g Graph) ([][]*node, []link, error) {
+	incoming := map[string]int{}
+	outgoing := map[string][]Edge{}
+	for _, e := range g.Edges {
+		incoming[e.To]++
+		outgoing[e.From] = append(outgoing[e.From], e)
+	}
+
+	// Kahn's algorithm, in declaration order
+	var order []string
+	var queue []string
+	for _, id := range g.Nodes {
+		if incoming[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+	depth := map[string]int{}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		order = append(order, id)
+		for _, e := range outgoing[id] {
+			if depth[id]+1 > depth[e.To] {
+				depth[e.To] = depth[id] + 1
+			}
+			if incoming[e.To]--; incoming[e.To] == 0 {
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	if len(order) < len(g.Nodes) {
+		return nil, nil, ErrCycle
+	}
+
+	var layers [][]*node
+	placed := map[string]*node{}
+	put := func(n *node, layer int) {
+		for len(layers) <= layer {
+			layers = append(layers, nil)
+		}
+		layers[layer] = append(layers[layer], n)
+	}
+	for _, id := range g.Nodes {
+		n := &node{id: id, label: g.Labels[id], rounded: g.Rounded[id]}
+		placed[id] = n
+		put(n, depth[id])
+	}
+
+	var links []link
+	for _, e := range g.Edges {
+		from := placed[e.From]
+		for layer := depth[e.From] + 1; layer < depth[e.To]; layer++ {
+			dummy := &node{dummy: true}
+			put(dummy, layer)
+			links = append(links, link{from: from, to: dummy, label: e.Label})
+			from = dummy
+			e.Label = ""
+		}
+		links = append(links, link{from: from, to: placed[e.To], label: e.Label})
+	}
+
+	// Nodes are ordered by the average position of the nodes linking to
+	// them, which untangles most simple flowcharts
+	for i := 1; i < len(layers); i++ {
+		position := map[*node]int{}
+		for j, n := range layers[i-1] {
+			position[n] = j
+		}
+		weight := map[*node]float64{}
+		for _, n := range layers[i] {
+			var sum, count float64
+			for _, l := range links {
+				if l.to == n {
+					sum += float64(position[l.from])
+					count++
+				}
+			}
+			if count > 0 {
+				weight[n] = sum / count
+			}
+		}
+		sort.SliceStable(layers[i], func(a, b int) bool {
+			return weight[layers[i][a]] < weight[layers[i][b]]
+		})
+	}
+	return layers, links, nil
+}
+
+// Directions of the lines passing through a cell of the canvas
+const (
+	up = 1 << iota
+	down
+	left
+	right
+)
+
+var lines = map[int]rune{
+	up: '│', down: '│', up | down: '│',
+	left: '─', right: '─', left | right: '─',
+	down | right: '┌', down | left: '┐', up | right: '└', up | left: '┘',
+	up | down | right: '├', up | down | left: '┤',
+	down | left | right: '┬', up | left | right: '┴',
+	up | down | left | right: '┼',
+}
+
+// canvas is the grid the flowchart is drawn on, the lines between the nodes
+// are joined into box-drawing characters
+type canvas struct {
+	runes [][]rune
+	lines [][]int
+}
+
+func newCanvas(width, height int) *canvas {
+	c := &canvas{runes: make([][]rune, height), lines: make([][]int, height)}
+	for y := range c.runes {
+		c.runes[y] = make([]rune, width)
+		c.lines[y] = make([]int, width)
+	}
+	return c
+}
+
+func (c *canvas) inside(x, y int) bool {
+	return y >= 0 && y < len(c.runes) && x >= 0 && x < len(c.runes[y])
+}
+
+func (c *canvas) set(x, y int, r rune) {
+	if c.inside(x, y) {
+		c.runes[y][x] = r
+	}
+}
+
+// wide fills the cells taken by the second half of wide characters
+const wide rune = -1
+
+// write writes the text from x on row y, wide characters take up two cells
+func (c *canvas) write(x, y int, s string) {
+	for _, r := range s {
+		c.set(x, y, r)
+		for w := lipgloss.Width(string(r)); w > 1; w-- {
+			x++
+			c.set(x, y, wide)
+		}
+		x++
+	}
+}
+
+// text writes the text from x on row y, unless it would overwrite anything
+// already drawn there
+func (c *canvas) text(x, y int, s string) {
+	for i := 0; i < lipgloss.Width(s); i++ {
+		if !c.inside(x+i, y) || c.runes[y][x+i] != 0 || c.lines[y][x+i] != 0 {
+			return
+		}
+	}
+	c.write(x, y, s)
+}
+
+// vertical draws a line between two rows of the column
+func (c *canvas) vertical(x, y1, y2 int) {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	for y := y1; y < y2; y++ {
+		if c.inside(x, y) && c.inside(x, y+1) {
+			c.lines[y][x] |= down
+			c.lines[y+1][x] |= up
+		}
+	}
+}
+
+// horizontal draws a line between two columns of the row
+func (c *canvas) horizontal(y, x1, x2 int) {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	for x := x1; x < x2; x++ {
+		if c.inside(x, y) && c.inside(x+1, y) {
+			c.lines[y][x] |= right
+			c.lines[y][x+1] |= left
+		}
+	}
+}
+
+// box draws the node with its label centered in it
+func (c *canvas) box(n *node) {
+	tl, tr, bl, br := '┌', '┐', '└', '┘'
+	if n.rounded {
+		tl, tr, bl, br = '╭', '╮', '╰', '╯'
+	}
+	right, bottom := n.x+n.w-1, n.y+n.h-1
+	for x := n.x + 1; x < right; x++ {
+		c.set(x, n.y, '─')
+		c.set(x, bottom, '─')
+	}
+	for y := n.y + 1; y < bottom; y++ {
+		c.set(n.x, y, '│')
+		c.set(right, y, '│')
+	}
+	c.set(n.x, n.y, tl)
+	c.set(right, n.y, tr)
+	c.set(n.x, bottom, bl)
+	c.set(right, bottom, br)
+	c.write(n.x+2, n.y+1, n.label)
+}
+
+func (c *canvas) String() string {
+	rows := make([]string, len(c.runes))
+	for y := range c.runes {
+		var b strings.Builder
+		for x, r := range c.runes[y] {
+			switch {
+			case r == wide:
+			case r != 0:
+				b.WriteRune(r)
+			case c.lines[y][x] != 0:
+				b.WriteRune(lines[c.lines[y][x]])
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		rows[y] = strings.TrimRight(b.String(), " ")
+	}
+	return strings.Join(rows, "\n")
+}
+
+// hasLabels returns whether any of the links are labelled, and the width of
+// the widest label
+func hasLabels(links []link) (bool, int) {
+	widest := 0
+	for _, l := range links {
+		if w := lipgloss.Width(l.label); w > widest {
+			widest = w
+		}
+	}
+	return widest > 0, widest
+}
+
+// boxWidth is the width of the box of the label, with a space on either side
+func boxWidth(label string) int {
+	return lipgloss.Width(label) + 4
+}
+
+// drawVertical draws the layers from top to bottom, each layer centered
+// above the next
+func drawVertical(layers [][]*node, links []link) string {
+	const nodeGap = 2
+	gap := 3
+	if labelled, _ := hasLabels(links); labelled {
+		gap = 4
+	}
+
+	width := 0
+	for _, layer := range layers {
+		w := 0
+		for i, n := range layer {
+			n.w, n.h = boxWidth(n.label), 3
+			if n.dummy {
+				n.w = 1
+			}
+			if i > 0 {
+				w += nodeGap
+			}
+			w += n.w
+		}
+		if w > width {
+			width = w
+		}
+	}
+	for i, layer := range layers {
+		w := 0
+		for j, n := range layer {
+			if j > 0 {
+				w += nodeGap
+			}
+			w += n.w
+		}
+		x := (width - w) / 2
+		for _, n := range layer {
+			n.x, n.y = x, i*(3+gap)
+			x += n.w + nodeGap
+		}
+	}
+	height := len(layers)*(3+gap) - gap
+	c := newCanvas(width+maxLabel(links)+2, height)
+
+	for _, layer := range layers {
+		for _, n := range layer {
+			if n.dummy {
+				c.vertical(n.x, n.y, n.y+n.h-1)
+				continue
+			}
+			c.box(n)
+		}
+	}
+	for _, l := range links {
+		fromX, toX := l.from.x+l.from.w/2, l.to.x+l.to.w/2
+		bottom := l.from.y + l.from.h - 1
+		bend := bottom + 2
+		c.vertical(fromX, bottom, bend)
+		c.horizontal(bend, fromX, toX)
+		if l.to.dummy {
+			c.vertical(toX, bend, l.to.y)
+		} else {
+			c.vertical(toX, bend, l.to.y-1)
+			c.set(toX, l.to.y-1, '▼')
+		}
+		if !l.from.dummy {
+			c.set(fromX, bottom, '┬')
+		}
+		if l.label != "" {
+			c.text(toX+2, l.to.y-1, l.label)
+		}
+	}
+	return c.String()
+}
+
+// drawHorizontal draws the layers from left to right, each layer centered
+// beside the next
+func drawHorizontal(layers [][]*node, links []link) string {
+	gap := 5
+	if labelled, widest := hasLabels(links); labelled {
+		gap = widest + 6
+	}
+
+	height := 0
+	for _, layer := range layers {
+		h := 0
+		for i, n := range layer {
+			n.w, n.h = boxWidth(n.label), 3
+			if n.dummy {
+				n.h = 1
+			}
+			if i > 0 {
+				h++
+			}
+			h += n.h
+		}
+		if h > height {
+			height = h
+		}
+	}
+	x := 0
+	for _, layer := range layers {
+		w := 0
+		h := 0
+		for j, n := range layer {
+			if !n.dummy && n.w > w {
+				w = n.w
+			}
+			if j > 0 {
+				h++
+			}
+			h += n.h
+		}
+		if w == 0 {
+			w = 1
+		}
+		y := (height - h) / 2
+		for _, n := range layer {
+			n.x, n.y, n.span = x, y, w
+			if n.dummy {
+				n.w = w
+			}
+			y += n.h + 1
+		}
+		x += w + gap
+	}
+	c := newCanvas(x-gap, height)
+
+	for _, layer := range layers {
+		for _, n := range layer {
+			if n.dummy {
+				c.horizontal(n.y, n.x, n.x+n.w-1)
+				continue
+			}
+			c.box(n)
+		}
+	}
+	for _, l := range links {
+		fromY, toY := l.from.y+l.from.h/2, l.to.y+l.to.h/2
+		edge := l.from.x + l.from.w - 1
+		bend := l.from.x + l.from.span + 1
+		c.horizontal(fromY, edge, bend)
+		c.vertical(bend, fromY, toY)
+		if l.to.dummy {
+			c.horizontal(toY, bend, l.to.x)
+		} else {
+			c.horizontal(toY, bend, l.to.x-1)
+			c.set(l.to.x-1, toY, '▶')
+		}
+		if !l.from.dummy {
+			c.set(edge, fromY, '├')
+		}
+		if l.label != "" {
+			c.text(bend+2, toY-1, l.label)
+		}
+	}
+	return c.String()
+}
+
+// maxLabel returns the width of the widest label of the links
+func maxLabel(links []link) int {
+	_, widest := hasLabels(links)
+	return widest
+}
@@ -0,0 +1,122 @@
+package diagram_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maaslalani/slides/internal/diagram"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMermaid(t *testing.T) {
+	g, err := diagram.ParseMermaid("graph LR\n  %% a comment\n  A[Write] -->|done| B(Present); B --> C\n  A -- skip --> C\n")
+	assert.NoError(t, err)
+	assert.True(t, g.Horizontal)
+	assert.Equal(t, []string{"A", "B", "C"}, g.Nodes)
+	assert.Equal(t, map[string]string{"A": "Write", "B": "Present", "C": "C"}, g.Labels)
+	assert.True(t, g.Rounded["B"])
+	assert.Equal(t, []diagram.Edge{
+		{From: "A", To: "B", Label: "done"},
+		{From: "B", To: "C"},
+		{From: "A", To: "C", Label: "skip"},
+	}, g.Edges)
+}
+
+func TestParseMermaid_unsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{name: "Diagram", source: "sequenceDiagram\n  A->>B: hi", err: "sequenceDiagram diagrams are not supported, only flowcharts"},
+		{name: "Direction", source: "graph BT\n  A --> B", err: "flowcharts laid out BT are not supported, only TD and LR"},
+		{name: "Subgraph", source: "graph TD\n  subgraph one\n  A --> B\n  end", err: "subgraph is not supported"},
+		{name: "Syntax", source: "graph TD\n  A --> B --x C", err: `could not parse "A --> B --x C"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := diagram.ParseMermaid(tt.source)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseDot(t *testing.T) {
+	g, err := diagram.ParseDot(`digraph deploy {
+  // the pipeline
+  rankdir=LR;
+  node [shape=box]
+  build -> test -> "ship it" [label="green"]
+  build [label="Build"]
+}`)
+	assert.NoError(t, err)
+	assert.True(t, g.Horizontal)
+	assert.Equal(t, []string{"build", "test", "ship it"}, g.Nodes)
+	assert.Equal(t, "Build", g.Labels["build"])
+	assert.Equal(t, []diagram.Edge{
+		{From: "build", To: "test", Label: "green"},
+		{From: "test", To: "ship it", Label: "green"},
+	}, g.Edges)
+
+	_, err = diagram.ParseDot("digraph { subgraph cluster { a -> b } }")
+	assert.EqualError(t, err, "subgraphs are not supported")
+}
+
+func TestDraw(t *testing.T) {
+	g, err := diagram.ParseMermaid("graph TD\n  A[Write] --> B[Present]")
+	assert.NoError(t, err)
+	got, err := diagram.Draw(g)
+	assert.NoError(t, err)
+	want := strings.Join([]string{
+		" ┌───────┐",
+		" │ Write │",
+		" └───┬───┘",
+		"     │",
+		"     │",
+		"     ▼",
+		"┌─────────┐",
+		"│ Present │",
+		"└─────────┘",
+	}, "\n")
+	assert.Equal(t, want, got)
+
+	g.Horizontal = true
+	got, err = diagram.Draw(g)
+	assert.NoError(t, err)
+	want = strings.Join([]string{
+		"┌───────┐     ┌─────────┐",
+		"│ Write ├────▶│ Present │",
+		"└───────┘     └─────────┘",
+	}, "\n")
+	assert.Equal(t, want, got)
+}
+
+func TestDraw_wideLabels(t *testing.T) {
+	g, err := diagram.ParseMermaid("graph LR\n  A[書く] -->|次| B[発表]")
+	assert.NoError(t, err)
+	got, err := diagram.Draw(g)
+	assert.NoError(t, err)
+	want := strings.Join([]string{
+		"┌──────┐   次   ┌──────┐",
+		"│ 書く ├───────▶│ 発表 │",
+		"└──────┘        └──────┘",
+	}, "\n")
+	assert.Equal(t, want, got)
+}
+
+func TestDraw_cycle(t *testing.T) {
+	g, err := diagram.ParseMermaid("graph TD\n  A --> B --> A")
+	assert.NoError(t, err)
+	_, err = diagram.Draw(g)
+	assert.Equal(t, diagram.ErrCycle, err)
+}
+
+func TestTransform(t *testing.T) {
+	got := diagram.Transform("# Flow\n\n```mermaid\ngraph LR\n  A --> B\n```\n\n```go\nfmt.Println()\n```")
+	want := "# Flow\n\n```\n┌───┐     ┌───┐\n│ A ├────▶│ B │\n└───┘     └───┘\n```\n\n```go\nfmt.Println()\n```"
+	assert.Equal(t, want, got)
+
+	// Diagrams which can't be drawn are shown as they are
+	source := "```mermaid\npie\n  \"a\": 1\n```"
+	assert.Equal(t, source+"\n\n> Diagram not drawn: pie diagrams are not supported, only flowcharts", diagram.Transform(source))
+}
@@ -0,0 +1,158 @@
+package diagram
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	reDotGraph = regexp.MustCompile(`(?s)^\s*(?:strict\s+)?(digraph|graph)\b[^{]*\{(.*)\}\s*$`)
+	reDotID    = `(?:"(?:[^"\\]|\\.)*"|[\w.]+)`
+	reDotEdge  = regexp.MustCompile(`\s*(->|--)\s*`)
+	reDotAttrs = regexp.MustCompile(`\[(.*)\]\s*$`)
+	reDotAttr  = regexp.MustCompile(`(\w+)\s*=\s*(` + reDotID + `)`)
+	reDotNode  = regexp.MustCompile(`^` + reDotID + `$`)
+	// reDotComment matches the /* */ comments of a graph
+	reDotComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+)
+
+// ParseDot parses a graphviz graph, such as
+//
+//	digraph {
+//	  write -> present [label="done"]
+//	}
+//
+// The labels of nodes and edges and the rankdir of the graph are used, other
+// attributes are ignored and subgraphs aren't supported.
+func ParseDot(source string) (Graph, error) {
+	g := newGraph()
+	match := reDotGraph.FindStringSubmatch(stripDotComments(source))
+	if match == nil {
+		return g, fmt.Errorf("could not find a graph")
+	}
+	for _, statement := range splitDot(match[2]) {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+		if strings.HasPrefix(statement, "subgraph") || strings.HasPrefix(statement, "{") {
+			return g, fmt.Errorf("subgraphs are not supported")
+		}
+
+		attrs := map[string]string{}
+		if loc := reDotAttrs.FindStringSubmatchIndex(statement); loc != nil {
+			for _, attr := range reDotAttr.FindAllStringSubmatch(statement[loc[2]:loc[3]], -1) {
+				attrs[attr[1]] = unquoteDot(attr[2])
+			}
+			statement = strings.TrimSpace(statement[:loc[0]])
+		}
+
+		// Settings of the graph, such as rankdir=LR
+		if name, value, ok := cutDot(statement, "="); ok {
+			if strings.TrimSpace(name) == "rankdir" {
+				switch unquoteDot(strings.TrimSpace(value)) {
+				case "TB":
+				case "LR":
+					g.Horizontal = true
+				default:
+					return g, fmt.Errorf("graphs laid out %s are not supported, only TB and LR", value)
+				}
+			}
+			continue
+		}
+		switch statement {
+		case "graph":
+			if rankdir, ok := attrs["rankdir"]; ok && rankdir == "LR" {
+				g.Horizontal = true
+			}
+			continue
+		case "node", "edge":
+			continue
+		}
+
+		ids := reDotEdge.Split(statement, -1)
+		for _, id := range ids {
+			if !reDotNode.MatchString(id) {
+				return g, fmt.Errorf("could not parse %q", statement)
+			}
+		}
+		if len(ids) == 1 {
+			g.add(unquoteDot(ids[0]), attrs["label"], attrs["shape"] == "ellipse" || attrs["shape"] == "oval")
+			continue
+		}
+		for i := range ids {
+			g.add(unquoteDot(ids[i]), "", false)
+			if i > 0 {
+				g.Edges = append(g.Edges, Edge{From: unquoteDot(ids[i-1]), To: unquoteDot(ids[i]), Label: attrs["label"]})
+			}
+		}
+	}
+	return g, nil
+}
+
+// splitDot splits the body of a graph into its statements, which are
+// separated by semicolons or new lines outside of quotes and brackets
+func splitDot(body string) []string {
+	var statements []string
+	var b strings.Builder
+	quoted, brackets := false, 0
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(body):
+			b.WriteByte(c)
+			i++
+			c = body[i]
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == '[':
+			brackets++
+		case !quoted && c == ']':
+			brackets--
+		case !quoted && brackets == 0 && (c == ';' || c == '\n'):
+			statements = append(statements, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return append(statements, b.String())
+}
+
+// stripDotComments removes the // and # line comments and /* */ comments of
+// the graph
+func stripDotComments(source string) string {
+	source = reDotComment.ReplaceAllString(source, "")
+	rows := strings.Split(source, "\n")
+	for i, row := range rows {
+		trimmed := strings.TrimSpace(row)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") {
+			rows[i] = ""
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// cutDot splits the statement around the first separator outside of quotes
+func cutDot(statement, sep string) (string, string, bool) {
+	quoted := false
+	for i := 0; i < len(statement); i++ {
+		switch {
+		case statement[i] == '"':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(statement[i:], sep):
+			return statement[:i], statement[i+len(sep):], true
+		}
+	}
+	return statement, "", false
+}
+
+// unquoteDot removes the quotes around an ID, along with the escapes of
+// quotes within it
+func unquoteDot(id string) string {
+	if len(id) >= 2 && id[0] == '"' && id[len(id)-1] == '"' {
+		return strings.ReplaceAll(id[1:len(id)-1], `\"`, `"`)
+	}
+	return id
+}
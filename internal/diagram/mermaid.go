@@ -0,0 +1,116 @@
+package diagram
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	reMermaidNode = regexp.MustCompile(`^(\w+)\s*(?:\(\((.*?)\)\)|\[(.*?)\]|\((.*?)\)|\{(.*?)\})?`)
+	// Arrows with their label between pipes, such as -->|yes|, or in the
+	// middle, such as -- yes -->
+	reMermaidEdge = regexp.MustCompile(`^\s*(?:(?:-->|---|-\.->|-\.-|==>|===)\s*(?:\|([^|]*)\|)?|--\s+([^-]+?)\s+-->|==\s+([^=]+?)\s+==>)\s*`)
+)
+
+// unsupportedMermaid are the statements of flowcharts which can't be drawn
+var unsupportedMermaid = []string{"subgraph", "classDef", "class", "style", "click", "linkStyle"}
+
+// ParseMermaid parses a mermaid flowchart, such as
+//
+//	graph TD
+//	  A[Write slides] --> B(Present them)
+//
+// Flowcharts are laid out from top to bottom or from left to right, other
+// diagrams and the statements styling flowcharts aren't supported.
+func ParseMermaid(source string) (Graph, error) {
+	g := newGraph()
+	statements := strings.FieldsFunc(source, func(r rune) bool {
+		return r == '\n' || r == ';'
+	})
+	header := true
+	for _, statement := range statements {
+		statement = strings.TrimSpace(statement)
+		if statement == "" || strings.HasPrefix(statement, "%%") {
+			continue
+		}
+		if header {
+			header = false
+			fields := strings.Fields(statement)
+			if fields[0] != "graph" && fields[0] != "flowchart" {
+				return g, fmt.Errorf("%s diagrams are not supported, only flowcharts", fields[0])
+			}
+			direction := "TD"
+			if len(fields) > 1 {
+				direction = fields[1]
+			}
+			switch direction {
+			case "TD", "TB":
+			case "LR":
+				g.Horizontal = true
+			default:
+				return g, fmt.Errorf("flowcharts laid out %s are not supported, only TD and LR", direction)
+			}
+			continue
+		}
+		keyword := strings.Fields(statement)[0]
+		for _, unsupported := range unsupportedMermaid {
+			if keyword == unsupported {
+				return g, fmt.Errorf("%s is not supported", keyword)
+			}
+		}
+		if err := parseMermaidChain(&g, statement); err != nil {
+			return g, err
+		}
+	}
+	if header {
+		return g, fmt.Errorf("the diagram is empty")
+	}
+	return g, nil
+}
+
+// parseMermaidChain parses a node followed by any number of arrows to other
+// nodes, such as A --> B --> C
+func parseMermaidChain(g *Graph, statement string) error {
+	rest := statement
+	previous := ""
+	label := ""
+	for {
+		match := reMermaidNode.FindStringSubmatch(rest)
+		if match == nil {
+			return fmt.Errorf("could not parse %q", statement)
+		}
+		id := match[1]
+		text, rounded := match[3], false
+		for _, group := range []string{match[2], match[4], match[5]} {
+			if group != "" {
+				text, rounded = group, true
+			}
+		}
+		g.add(id, unquote(text), rounded)
+		if previous != "" {
+			g.Edges = append(g.Edges, Edge{From: previous, To: id, Label: label})
+		}
+		rest = rest[len(match[0]):]
+		if strings.TrimSpace(rest) == "" {
+			return nil
+		}
+
+		edge := reMermaidEdge.FindStringSubmatch(rest)
+		if edge == nil {
+			return fmt.Errorf("could not parse %q", statement)
+		}
+		label = unquote(strings.TrimSpace(edge[1] + edge[2] + edge[3]))
+		previous = id
+		rest = rest[len(edge[0]):]
+	}
+}
+
+// unquote removes the quotes around a label
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
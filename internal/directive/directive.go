@@ -0,0 +1,89 @@
+// Package directive implements parsing of per-slide directives which are
+// written as HTML comments, such as <!-- theme: dark -->
+package directive
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Directive is a single name and value pair written in a slide
+type Directive struct {
+	Name  string
+	Value string
+}
+
+// ?: means non-capture group
+var re = regexp.MustCompile(`<!--\s*([\w-]+)\s*:\s*(?s:(.*?))\s*-->[ \t]*\n?`)
+
+// Parse returns all of the directives in the given slide in the order they
+// appear
+func Parse(slide string) []Directive {
+	var rv []Directive
+	for _, match := range Matches(slide) {
+		rv = append(rv, match.Directive)
+	}
+	return rv
+}
+
+// Match is a directive along with the raw text it was parsed from
+type Match struct {
+	Directive
+	Raw string
+}
+
+// Matches returns all of the directives in the given slide along with the
+// text they were parsed from, excluding their trailing newline
+func Matches(slide string) []Match {
+	var rv []Match
+	for _, match := range re.FindAllStringSubmatch(slide, -1) {
+		rv = append(rv, Match{
+			Directive: Directive{
+				Name:  strings.ToLower(match[1]),
+				Value: match[2],
+			},
+			Raw: strings.TrimRight(match[0], " \t\n"),
+		})
+	}
+	return rv
+}
+
+// Get returns the value of the last directive with the given name in the
+// slide and whether such a directive exists
+func Get(slide string, name string) (string, bool) {
+	var (
+		value  string
+		exists bool
+	)
+	for _, d := range Parse(slide) {
+		if d.Name == name {
+			value = d.Value
+			exists = true
+		}
+	}
+	return value, exists
+}
+
+// All returns the values of every directive with the given name in the slide
+func All(slide string, name string) []string {
+	var rv []string
+	for _, d := range Parse(slide) {
+		if d.Name == name {
+			rv = append(rv, d.Value)
+		}
+	}
+	return rv
+}
+
+// Strip removes the directives with the given names from the slide
+func Strip(slide string, names ...string) string {
+	return re.ReplaceAllStringFunc(slide, func(match string) string {
+		name := strings.ToLower(re.FindStringSubmatch(match)[1])
+		for _, n := range names {
+			if n == name {
+				return ""
+			}
+		}
+		return match
+	})
+}
@@ -0,0 +1,55 @@
+package directive_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/directive"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	slide := `<!-- theme: dark -->
+# Slide
+
+<!--Note:first line
+second line -->
+<!-- not a directive -->
+text <!-- note: inline -->`
+
+	want := []directive.Directive{
+		{Name: "theme", Value: "dark"},
+		{Name: "note", Value: "first line\nsecond line"},
+		{Name: "note", Value: "inline"},
+	}
+	assert.Equal(t, want, directive.Parse(slide))
+}
+
+func TestGet(t *testing.T) {
+	slide := "<!-- theme: dark -->\n<!-- theme: light -->\n# Slide"
+
+	value, ok := directive.Get(slide, "theme")
+	assert.True(t, ok)
+	assert.Equal(t, "light", value)
+
+	_, ok = directive.Get(slide, "background")
+	assert.False(t, ok)
+}
+
+func TestAll(t *testing.T) {
+	slide := "<!-- key: a -> 2 -->\n<!-- key: b -> 3 -->\n<!-- theme: dark -->"
+	assert.Equal(t, []string{"a -> 2", "b -> 3"}, directive.All(slide, "key"))
+	assert.Nil(t, directive.All(slide, "note"))
+}
+
+func TestStrip(t *testing.T) {
+	slide := "<!-- theme: dark -->\n# Slide\n<!-- note: hidden -->\n<!-- other -->\ntext"
+	assert.Equal(t, "# Slide\n<!-- other -->\ntext", directive.Strip(slide, "theme", "note"))
+}
+
+func TestMatches(t *testing.T) {
+	slide := "<!--Theme:dark-->  \n# Slide"
+	want := []directive.Match{
+		{Directive: directive.Directive{Name: "theme", Value: "dark"}, Raw: "<!--Theme:dark-->"},
+	}
+	assert.Equal(t, want, directive.Matches(slide))
+}
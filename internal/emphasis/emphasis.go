@@ -0,0 +1,190 @@
+// Package emphasis implements rendering code blocks with {hl=3-5} after
+// their language, which are shown with line numbers and the given lines
+// highlighted while the other lines are dimmed
+package emphasis
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maaslalani/slides/internal/code"
+	"github.com/maaslalani/slides/internal/placeholder"
+)
+
+const (
+	highlight = "\x1b[48;5;238m"
+	dim       = "\x1b[2m"
+	reset     = "\x1b[0m"
+)
+
+var reStyle = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+var gutterStyle = lipgloss.NewStyle().Faint(true)
+
+// Block is a code block with lines to highlight
+type Block struct {
+	// Markdown is the code block without the lines to highlight after its
+	// language, so that it can be rendered on its own
+	Markdown string
+	// First is the number of the first line of the code, blank lines at the
+	// start of the code aren't rendered
+	First int
+	// Lines is the number of lines of the code
+	Lines     int
+	Highlight []int
+}
+
+// Extract replaces the code blocks of the markdown which highlight lines with
+// placeholders, and returns the blocks
+func Extract(markdown string) (string, []Block) {
+	var (
+		lines  = strings.Split(markdown, "\n")
+		out    []string
+		blocks []Block
+		fence  string
+		open   string
+		body   []string
+		block  *Block
+	)
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+			info := strings.TrimLeft(trimmed, fence[:1])
+			if highlighted := code.Highlighted(info); highlighted != nil {
+				open = line
+				var language string
+				if fields := strings.Fields(info); len(fields) > 0 && !strings.HasPrefix(fields[0], "{") {
+					language = fields[0]
+				}
+				block = &Block{Markdown: fence + language, Highlight: highlighted}
+				body = nil
+				continue
+			}
+		case fence != "" && strings.HasPrefix(trimmed, fence):
+			fence = ""
+			if block != nil {
+				// Blank lines around the code aren't rendered by glamour,
+				// so they are left out of the numbering
+				first := 0
+				for first < len(body) && strings.TrimSpace(body[first]) == "" {
+					first++
+				}
+				last := len(body)
+				for last > first && strings.TrimSpace(body[last-1]) == "" {
+					last--
+				}
+				block.First = first + 1
+				block.Lines = last - first
+				block.Markdown += "\n" + strings.Join(body[first:last], "\n") + "\n" + trimmed[:3]
+				out = append(out, placeholder.CodeBlock.Mark(len(blocks)))
+				blocks = append(blocks, *block)
+				block = nil
+				continue
+			}
+		case block != nil:
+			body = append(body, line)
+			continue
+		}
+		out = append(out, line)
+	}
+	if block != nil {
+		// A code block which is never closed is left as it is
+		out = append(out, open)
+		out = append(out, body...)
+	}
+	return strings.Join(out, "\n"), blocks
+}
+
+// GutterWidth returns the width of the line numbers of the block
+func (b Block) GutterWidth() int {
+	return len(strconv.Itoa(b.First+b.Lines-1)) + 3
+}
+
+// Restore replaces the lines of the rendered slide containing placeholders
+// with their code blocks, which are rendered by calling render with the
+// markdown of the block and the width left beside the line numbers
+func Restore(rendered string, blocks []Block, width int, render func(markdown string, width int) string) string {
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		n, _, ok := placeholder.CodeBlock.Find(line)
+		if !ok || n >= len(blocks) {
+			continue
+		}
+		block := blocks[n]
+		lines[i] = Decorate(render(block.Markdown, width-block.GutterWidth()), block)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Decorate adds line numbers to the lines of the rendered code block,
+// highlighting the lines of the block and dimming the other lines
+func Decorate(rendered string, block Block) string {
+	lines := strings.Split(rendered, "\n")
+	start := 0
+	for start < len(lines) && strings.TrimSpace(reStyle.ReplaceAllString(lines[start], "")) == "" {
+		start++
+	}
+	end := start + block.Lines
+	if end > len(lines) {
+		end = len(lines)
+	}
+	lines = lines[start:end]
+
+	// The line numbers are placed after the margin of the code block
+	margin := -1
+	for _, line := range lines {
+		plain := reStyle.ReplaceAllString(line, "")
+		if strings.TrimSpace(plain) == "" {
+			continue
+		}
+		if indent := len(plain) - len(strings.TrimLeft(plain, " ")); margin < 0 || indent < margin {
+			margin = indent
+		}
+	}
+	if margin < 0 {
+		margin = 0
+	}
+
+	highlighted := map[int]bool{}
+	for _, n := range block.Highlight {
+		highlighted[n] = true
+	}
+	digits := block.GutterWidth() - 3
+	for i, line := range lines {
+		n := block.First + i
+		style := dim
+		if highlighted[n] {
+			style = highlight
+		}
+		gutter := gutterStyle.Render(fmt.Sprintf("%*d │ ", digits, n))
+		lines[i] = strings.Repeat(" ", margin) + gutter + style +
+			strings.ReplaceAll(trimIndent(line, margin), reset, reset+style) + reset
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimIndent removes the first n spaces of the line, leaving the escape
+// sequences styling the line in place
+func trimIndent(line string, n int) string {
+	var b strings.Builder
+	for len(line) > 0 {
+		if loc := reStyle.FindStringIndex(line); loc != nil && loc[0] == 0 {
+			b.WriteString(line[:loc[1]])
+			line = line[loc[1]:]
+			continue
+		}
+		if n > 0 && line[0] == ' ' {
+			n--
+			line = line[1:]
+			continue
+		}
+		b.WriteString(line)
+		break
+	}
+	return b.String()
+}
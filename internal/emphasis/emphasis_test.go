@@ -0,0 +1,48 @@
+package emphasis_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/maaslalani/slides/internal/emphasis"
+	"github.com/stretchr/testify/assert"
+)
+
+var reStyle = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestExtract(t *testing.T) {
+	markdown := "# Code\n```go {hl=2}\n\nfmt.Println(1)\nfmt.Println(2)\n\n```\n```go\nfmt.Println(3)\n```"
+
+	got, blocks := emphasis.Extract(markdown)
+
+	assert.Equal(t, "# Code\n0\n```go\nfmt.Println(3)\n```", got)
+	assert.Equal(t, []emphasis.Block{{
+		Markdown:  "```go\nfmt.Println(1)\nfmt.Println(2)\n```",
+		First:     2,
+		Lines:     2,
+		Highlight: []int{2},
+	}}, blocks)
+}
+
+func TestExtract_unterminated(t *testing.T) {
+	markdown := "# Code\n~~~go {hl=1}\nfmt.Println(1)"
+
+	got, blocks := emphasis.Extract(markdown)
+
+	assert.Equal(t, markdown, got)
+	assert.Empty(t, blocks)
+}
+
+func TestRestore(t *testing.T) {
+	_, blocks := emphasis.Extract("```go {hl=2}\nfirst\nsecond\n```")
+
+	got := emphasis.Restore("text\n  0  \nmore", blocks, 20, func(markdown string, width int) string {
+		assert.Equal(t, "```go\nfirst\nsecond\n```", markdown)
+		assert.Equal(t, 16, width)
+		return "\n  first\n  second\n"
+	})
+
+	assert.Equal(t, "text\n  1 │ first\n  2 │ second\nmore", reStyle.ReplaceAllString(got, ""))
+	assert.True(t, strings.Contains(got, "\x1b[48;5;238msecond"))
+}
@@ -0,0 +1,148 @@
+// Package export implements converting slides to the formats of other
+// presentation tools
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/maaslalani/slides/internal/directive"
+)
+
+// Deck is a presentation to export
+type Deck struct {
+	Author     string
+	Date       string
+	Theme      string
+	Paging     string
+	Background string
+	Slides     []string
+	// Style is the style config of the theme, for formats which render the
+	// slides themselves
+	Style ansi.StyleConfig
+	// Render renders the slides as they are presented at the given width,
+	// for formats which draw the slides
+	Render func(width int) []string
+}
+
+// Exporter converts a deck to another format, warnings are reported for
+// anything which can't be converted
+type Exporter func(deck Deck, warn func(string)) string
+
+// Formats lists the supported export formats
+var Formats = map[string]Exporter{
+	"html":   HTML,
+	"marp":   Marp,
+	"pdf":    PDF,
+	"reveal": Reveal,
+}
+
+// Names returns the names of the supported export formats
+func Names() []string {
+	var names []string
+	for name := range Formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+const delimiter = "\n---\n"
+
+// Marp converts the deck to Marp markdown
+func Marp(deck Deck, warn func(string)) string {
+	header := []string{"marp: true"}
+	if deck.Theme != "" && deck.Theme != "default" {
+		header = append(header, "theme: "+deck.Theme)
+	}
+	if deck.Paging != "" {
+		header = append(header, "paginate: true")
+	}
+	if footer := strings.TrimSpace(deck.Author + " " + deck.Date); footer != "" {
+		header = append(header, fmt.Sprintf("footer: %q", footer))
+	}
+	if deck.Background != "" {
+		header = append(header, fmt.Sprintf("backgroundImage: url(%q)", deck.Background))
+	}
+
+	slides := make([]string, len(deck.Slides))
+	for i, slide := range deck.Slides {
+		slides[i] = convert(slide, i, warn, map[string]func(string) string{
+			// Marp presents all comments as speaker notes
+			"note":       func(v string) string { return "<!-- " + v + " -->" },
+			"theme":      func(v string) string { return "<!-- _theme: " + v + " -->" },
+			"background": func(v string) string { return fmt.Sprintf("<!-- _backgroundImage: url(%q) -->", v) },
+		})
+	}
+
+	return "---\n" + strings.Join(header, "\n") + delimiter + strings.Join(slides, delimiter)
+}
+
+// Reveal converts the deck to reveal.js markdown
+func Reveal(deck Deck, warn func(string)) string {
+	var header []string
+	if deck.Theme != "" && deck.Theme != "default" {
+		header = append(header, "theme: "+deck.Theme)
+	}
+	if deck.Author != "" {
+		header = append(header, fmt.Sprintf("author: %q", deck.Author))
+	}
+	if deck.Date != "" {
+		header = append(header, fmt.Sprintf("date: %q", deck.Date))
+	}
+
+	slides := make([]string, len(deck.Slides))
+	for i, slide := range deck.Slides {
+		var notes []string
+		slide = convert(slide, i, warn, map[string]func(string) string{
+			"note": func(v string) string {
+				notes = append(notes, v)
+				return ""
+			},
+			"background": func(v string) string { return fmt.Sprintf("<!-- .slide: data-background=%q -->", v) },
+		})
+		if deck.Background != "" {
+			if _, ok := directive.Get(slide, "background"); !ok && !strings.Contains(slide, "data-background") {
+				slide = fmt.Sprintf("<!-- .slide: data-background=%q -->\n", deck.Background) + slide
+			}
+		}
+		if len(notes) > 0 {
+			slide = strings.TrimRight(slide, "\n") + "\n\nNote: " + strings.Join(notes, "\n")
+		}
+		slides[i] = slide
+	}
+
+	md := strings.Join(slides, delimiter)
+	if len(header) > 0 {
+		md = "---\n" + strings.Join(header, "\n") + delimiter + md
+	}
+	return md
+}
+
+// convert replaces the directives of the slide using the given converters,
+// directives without a converter are kept as comments and reported
+func convert(slide string, page int, warn func(string), converters map[string]func(string) string) string {
+	for _, d := range directive.Parse(slide) {
+		raw := fmt.Sprintf("<!-- %s: %s -->", d.Name, d.Value)
+		if c, ok := converters[d.Name]; ok {
+			slide = replaceDirective(slide, d, c(d.Value))
+			continue
+		}
+		warn(fmt.Sprintf("slide %d: unsupported directive %q kept as a comment", page+1, d.Name))
+		slide = replaceDirective(slide, d, raw)
+	}
+	return slide
+}
+
+// replaceDirective replaces the first occurrence of the directive, in any of
+// its spellings, with the replacement
+func replaceDirective(slide string, d directive.Directive, replacement string) string {
+	for _, m := range directive.Matches(slide) {
+		if m.Directive == d {
+			return strings.Replace(slide, m.Raw, replacement, 1)
+		}
+	}
+	return slide
+}
@@ -0,0 +1,114 @@
+package export_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/maaslalani/slides/internal/export"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarp(t *testing.T) {
+	deck := export.Deck{
+		Author: "Maas",
+		Theme:  "dark",
+		Paging: "Slide %d / %d",
+		Slides: []string{
+			"# Welcome\n<!-- note: say hello -->",
+			"<!-- theme: light -->\n# Two\n<!-- key: a -> 1 -->",
+		},
+	}
+	var warnings []string
+	got := export.Marp(deck, func(w string) { warnings = append(warnings, w) })
+
+	want := "---\nmarp: true\ntheme: dark\npaginate: true\nfooter: \"Maas\"\n---\n" +
+		"# Welcome\n<!-- say hello -->\n---\n" +
+		"<!-- _theme: light -->\n# Two\n<!-- key: a -> 1 -->"
+	assert.Equal(t, want, got)
+	assert.Equal(t, []string{`slide 2: unsupported directive "key" kept as a comment`}, warnings)
+}
+
+func TestReveal(t *testing.T) {
+	deck := export.Deck{
+		Background: "bg.png",
+		Slides: []string{
+			"# Welcome\n<!-- note: say hello -->",
+			"<!-- background: other.png -->\n# Two",
+		},
+	}
+	var warnings []string
+	got := export.Reveal(deck, func(w string) { warnings = append(warnings, w) })
+
+	want := "<!-- .slide: data-background=\"bg.png\" -->\n# Welcome\n\nNote: say hello\n---\n" +
+		"<!-- .slide: data-background=\"other.png\" -->\n# Two"
+	assert.Equal(t, want, got)
+	assert.Empty(t, warnings)
+}
+
+func TestHTML(t *testing.T) {
+	color := "#ff0000"
+	deck := export.Deck{
+		Author: "Maas",
+		Paging: "Slide %d / %d",
+		Slides: []string{
+			"# Welcome\n<!-- note: say <hello> -->",
+			"```go\nfunc main() {}\n```",
+		},
+		Style: ansi.StyleConfig{H1: ansi.StyleBlock{StylePrimitive: ansi.StylePrimitive{Color: &color}}},
+	}
+	var warnings []string
+	got := export.HTML(deck, func(w string) { warnings = append(warnings, w) })
+
+	assert.Contains(t, got, "<title>Welcome</title>")
+	assert.Contains(t, got, "h1 { color: #ff0000; }")
+	assert.Contains(t, got, "<section id=\"slide-1\">\n<header><span class=\"author\">Maas</span><span class=\"paging\">Slide 1 / 2</span></header>")
+	assert.Contains(t, got, "<h1>Welcome</h1>")
+	assert.Contains(t, got, "<aside class=\"notes\">say &lt;hello&gt;</aside>")
+	assert.Contains(t, got, "<span class=\"kd\">func</span>")
+	assert.Contains(t, got, ".chroma .kd {")
+	assert.Empty(t, warnings)
+}
+
+func TestPDF(t *testing.T) {
+	deck := export.Deck{
+		Paging: "%d / %d",
+		Slides: []string{"# Welcome", "# Long"},
+		Render: func(width int) []string {
+			assert.Equal(t, export.PDFColumns, width)
+			long := strings.Repeat("line\n", 50)
+			return []string{"\x1b[1;38;5;212mWelcome\x1b[0m (to) • slides", long}
+		},
+	}
+	var warnings []string
+	got := export.PDF(deck, func(w string) { warnings = append(warnings, w) })
+
+	assert.True(t, strings.HasPrefix(got, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(got, "%%EOF"))
+	// The long slide continues on a second page
+	assert.Contains(t, got, "/Count 3")
+	assert.Contains(t, got, "1.000 0.529 0.843 rg BT /F2 12 Tf 36.00 544.60 Td (Welcome) Tj ET")
+	assert.Contains(t, got, "( \\(to\\) \\225 slides)")
+	assert.Contains(t, got, "(1 / 2)")
+	assert.Contains(t, got, "(2 / 2 \\(1/2\\))")
+	assert.Contains(t, got, "(2 / 2 \\(2/2\\))")
+	assert.Empty(t, warnings)
+
+	// Every object is found at the offset listed in the cross-reference table
+	xref := got[strings.LastIndex(got, "xref\n"):]
+	entries := strings.Split(xref, "\n")[3:]
+	for i, entry := range entries {
+		if !strings.HasSuffix(entry, " n ") {
+			break
+		}
+		offset, err := strconv.Atoi(entry[:10])
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(got[offset:], fmt.Sprintf("%d 0 obj", i+1)))
+	}
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, []string{"html", "marp", "pdf", "reveal"}, export.Names())
+}
@@ -0,0 +1,310 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	chromahtml "github.com/alecthomas/chroma/formatters/html"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/maaslalani/slides/internal/fragment"
+	"github.com/maaslalani/slides/internal/outline"
+	"github.com/maaslalani/slides/internal/progress"
+	"github.com/muesli/termenv"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// HTML converts the deck to a single self-contained HTML file, with a
+// section for each slide. The colors of the theme are inlined as CSS.
+func HTML(deck Deck, warn func(string)) string {
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+	style := codeStyle(deck.Style)
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(
+			// Slides may contain HTML, such as the comments of directives
+			goldmarkhtml.WithUnsafe(),
+			renderer.WithNodeRenderers(
+				util.Prioritized(&codeRenderer{formatter: formatter, style: style}, 100),
+			),
+		),
+	)
+
+	var b strings.Builder
+	title := "Slides"
+	if len(deck.Slides) > 0 {
+		if t := outline.Title(deck.Slides[0]); t != "" {
+			title = t
+		}
+	}
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>" + html.EscapeString(title) + "</title>\n<style>\n")
+	b.WriteString(stylesheet(deck))
+	var css bytes.Buffer
+	_ = formatter.WriteCSS(&css, style)
+	b.Write(css.Bytes())
+	b.WriteString("</style>\n</head>\n<body>\n")
+
+	for i, slide := range deck.Slides {
+		var notes []string
+		var background string
+		slide = convert(slide, i, warn, map[string]func(string) string{
+			"note": func(v string) string {
+				notes = append(notes, v)
+				return ""
+			},
+			"background": func(v string) string {
+				background = v
+				return ""
+			},
+		})
+
+		// Every fragment of the slide is shown at once
+		slide = fragment.Reveal(slide, fragment.Count(slide)-1)
+		var content bytes.Buffer
+		if err := md.Convert([]byte(slide), &content); err != nil {
+			warn(fmt.Sprintf("slide %d: %s", i+1, err))
+		}
+
+		if background != "" {
+			fmt.Fprintf(&b, "<section id=\"slide-%d\" style=\"background: url(&quot;%s&quot;) center / cover no-repeat\">\n", i+1, html.EscapeString(background))
+		} else {
+			fmt.Fprintf(&b, "<section id=\"slide-%d\">\n", i+1)
+		}
+		b.WriteString("<header>")
+		if deck.Author != "" {
+			b.WriteString("<span class=\"author\">" + html.EscapeString(deck.Author) + "</span>")
+		}
+		if deck.Date != "" {
+			b.WriteString("<span class=\"date\">" + html.EscapeString(deck.Date) + "</span>")
+		}
+		if paging := paging(deck.Paging, i+1, len(deck.Slides)); paging != "" {
+			b.WriteString("<span class=\"paging\">" + html.EscapeString(paging) + "</span>")
+		}
+		b.WriteString("</header>\n<div class=\"content\">\n")
+		b.Write(content.Bytes())
+		b.WriteString("</div>\n")
+		if len(notes) > 0 {
+			b.WriteString("<aside class=\"notes\">" + html.EscapeString(strings.Join(notes, "\n")) + "</aside>\n")
+		}
+		b.WriteString("</section>\n")
+	}
+
+	b.WriteString("</body>\n</html>")
+	return b.String()
+}
+
+// paging formats the page number in the same way as the status bar
+func paging(format string, page, total int) string {
+	if format == progress.Bar {
+		format = "%d / %d"
+	}
+	switch strings.Count(format, "%d") {
+	case 2:
+		return fmt.Sprintf(format, page, total)
+	case 1:
+		return fmt.Sprintf(format, page)
+	default:
+		return format
+	}
+}
+
+// stylesheet returns the CSS of the theme for the slides
+func stylesheet(deck Deck) string {
+	doc := deck.Style.Document.StylePrimitive
+	background := "#1e1e1e"
+	if deck.Theme == "light" {
+		background = "#ffffff"
+	}
+	if c := color(doc.BackgroundColor); c != "" {
+		background = c
+	}
+	if deck.Background != "" {
+		background += fmt.Sprintf(" url(%q) center / cover no-repeat", deck.Background)
+	}
+
+	rules := []string{
+		"html { scroll-snap-type: y mandatory; }",
+		"body { margin: 0; font-family: monospace; background: " + background + "; }",
+		"section { box-sizing: border-box; min-height: 100vh; padding: 2em 4em; scroll-snap-align: start; }",
+		"section header { display: flex; gap: 1em; opacity: 0.7; }",
+		"section header .paging { margin-left: auto; }",
+		"aside.notes { display: none; }",
+		"pre { padding: 1em; overflow-x: auto; }",
+		rule("section", doc),
+		rule("h1", deck.Style.H1.StylePrimitive),
+		rule("h2, h3, h4, h5, h6", deck.Style.Heading.StylePrimitive),
+		rule("h2", deck.Style.H2.StylePrimitive),
+		rule("h3", deck.Style.H3.StylePrimitive),
+		rule("a", deck.Style.Link),
+		rule("blockquote", deck.Style.BlockQuote.StylePrimitive),
+		rule(":not(pre) > code", deck.Style.Code.StylePrimitive),
+		rule("strong", deck.Style.Strong),
+		rule("em", deck.Style.Emph),
+	}
+
+	var b strings.Builder
+	for _, r := range rules {
+		if r != "" {
+			b.WriteString(r + "\n")
+		}
+	}
+	return b.String()
+}
+
+// rule returns the CSS rule for the style, or an empty string if the style
+// doesn't set anything which can be expressed in CSS
+func rule(selector string, style ansi.StylePrimitive) string {
+	var declarations []string
+	if c := color(style.Color); c != "" {
+		declarations = append(declarations, "color: "+c)
+	}
+	if c := color(style.BackgroundColor); c != "" {
+		declarations = append(declarations, "background-color: "+c)
+	}
+	if style.Bold != nil && *style.Bold {
+		declarations = append(declarations, "font-weight: bold")
+	}
+	if style.Italic != nil && *style.Italic {
+		declarations = append(declarations, "font-style: italic")
+	}
+	if style.Underline != nil && *style.Underline {
+		declarations = append(declarations, "text-decoration: underline")
+	}
+	if len(declarations) == 0 {
+		return ""
+	}
+	return selector + " { " + strings.Join(declarations, "; ") + "; }"
+}
+
+// color converts a color of the theme to a CSS color, themes use either hex
+// colors or ANSI 256 color codes
+func color(c *string) string {
+	if c == nil || *c == "" {
+		return ""
+	}
+	if strings.HasPrefix(*c, "#") {
+		return *c
+	}
+	n, err := strconv.Atoi(*c)
+	if err != nil || n < 0 || n > 255 {
+		return ""
+	}
+	return termenv.ANSI256Color(n).String()
+}
+
+// codeStyle returns the chroma style of the theme's code blocks
+func codeStyle(config ansi.StyleConfig) *chroma.Style {
+	rules := config.CodeBlock
+	if rules.Chroma == nil {
+		if rules.Theme != "" {
+			return styles.Get(rules.Theme)
+		}
+		return styles.Fallback
+	}
+
+	c := rules.Chroma
+	entries := map[chroma.TokenType]ansi.StylePrimitive{
+		chroma.Text:                c.Text,
+		chroma.Error:               c.Error,
+		chroma.Comment:             c.Comment,
+		chroma.CommentPreproc:      c.CommentPreproc,
+		chroma.Keyword:             c.Keyword,
+		chroma.KeywordReserved:     c.KeywordReserved,
+		chroma.KeywordNamespace:    c.KeywordNamespace,
+		chroma.KeywordType:         c.KeywordType,
+		chroma.Operator:            c.Operator,
+		chroma.Punctuation:         c.Punctuation,
+		chroma.Name:                c.Name,
+		chroma.NameBuiltin:         c.NameBuiltin,
+		chroma.NameTag:             c.NameTag,
+		chroma.NameAttribute:       c.NameAttribute,
+		chroma.NameClass:           c.NameClass,
+		chroma.NameConstant:        c.NameConstant,
+		chroma.NameDecorator:       c.NameDecorator,
+		chroma.NameException:       c.NameException,
+		chroma.NameFunction:        c.NameFunction,
+		chroma.NameOther:           c.NameOther,
+		chroma.Literal:             c.Literal,
+		chroma.LiteralNumber:       c.LiteralNumber,
+		chroma.LiteralDate:         c.LiteralDate,
+		chroma.LiteralString:       c.LiteralString,
+		chroma.LiteralStringEscape: c.LiteralStringEscape,
+		chroma.GenericDeleted:      c.GenericDeleted,
+		chroma.GenericEmph:         c.GenericEmph,
+		chroma.GenericInserted:     c.GenericInserted,
+		chroma.GenericStrong:       c.GenericStrong,
+		chroma.GenericSubheading:   c.GenericSubheading,
+		chroma.Background:          c.Background,
+	}
+	builder := chroma.NewStyleBuilder("slides")
+	for token, primitive := range entries {
+		var entry []string
+		if fg := color(primitive.Color); fg != "" {
+			entry = append(entry, fg)
+		}
+		if bg := color(primitive.BackgroundColor); bg != "" {
+			entry = append(entry, "bg:"+bg)
+		}
+		if primitive.Bold != nil && *primitive.Bold {
+			entry = append(entry, "bold")
+		}
+		if primitive.Italic != nil && *primitive.Italic {
+			entry = append(entry, "italic")
+		}
+		if primitive.Underline != nil && *primitive.Underline {
+			entry = append(entry, "underline")
+		}
+		if len(entry) > 0 {
+			builder.Add(token, strings.Join(entry, " "))
+		}
+	}
+	style, err := builder.Build()
+	if err != nil {
+		return styles.Fallback
+	}
+	return style
+}
+
+// codeRenderer renders fenced code blocks with the classes of the chroma
+// tokens, so the highlighting is styled by the stylesheet
+type codeRenderer struct {
+	formatter *chromahtml.Formatter
+	style     *chroma.Style
+}
+
+func (r *codeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.render)
+}
+
+func (r *codeRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+	block := node.(*ast.FencedCodeBlock)
+	var code strings.Builder
+	for i := 0; i < block.Lines().Len(); i++ {
+		line := block.Lines().At(i)
+		code.Write(line.Value(source))
+	}
+
+	lexer := lexers.Get(string(block.Language(source)))
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, code.String())
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	return ast.WalkSkipChildren, r.formatter.Format(w, r.style, iterator)
+}
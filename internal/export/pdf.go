@@ -0,0 +1,348 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/maaslalani/slides/styles"
+)
+
+// The slides are drawn on landscape A4 pages in a monospaced font, so that
+// they are laid out exactly as they are in the terminal
+const (
+	// PDFColumns is the width, in characters, the slides are rendered at
+	PDFColumns = 100
+
+	pageWidth  = 842.0
+	pageHeight = 595.0
+	margin     = 36.0
+	fontSize   = 12.0
+	// The advance of every glyph of Courier is 600/1000 of the font size
+	charWidth  = fontSize * 0.6
+	lineHeight = fontSize * 1.2
+	// The space at the bottom of the page reserved for the paging
+	footerHeight = 2 * lineHeight
+)
+
+// pdfRows is the number of lines of a slide which fit on a page, longer
+// slides continue on the following pages
+var pdfRows = rows(pageHeight - 2*margin - footerHeight)
+
+func rows(height float64) int {
+	return int(height / lineHeight)
+}
+
+// PDF draws each slide as it is presented in the terminal on its own page,
+// slides which are too long for a page continue on the next one
+func PDF(deck Deck, warn func(string)) string {
+	var rendered []string
+	if deck.Render != nil {
+		rendered = deck.Render(PDFColumns)
+	} else {
+		warn("slides can't be rendered, exporting the markdown instead")
+		rendered = deck.Slides
+	}
+
+	background := rgb(pageBackground(deck))
+	foreground := rgb(pageForeground(deck))
+
+	var pages []string
+	for i, slide := range rendered {
+		number := paging(deck.Paging, i+1, len(rendered))
+		chunks := styles.Paginate(strings.TrimRight(slide, "\n"), pdfRows)
+		for j, chunk := range chunks {
+			footer := number
+			if len(chunks) > 1 {
+				footer = strings.TrimSpace(fmt.Sprintf("%s (%d/%d)", number, j+1, len(chunks)))
+			}
+			pages = append(pages, drawPage(chunk, footer, background, foreground))
+		}
+	}
+	return writePDF(pages)
+}
+
+// pageBackground returns the background color of the theme
+func pageBackground(deck Deck) string {
+	if c := color(deck.Style.Document.BackgroundColor); c != "" {
+		return c
+	}
+	if deck.Theme == "light" {
+		return "#ffffff"
+	}
+	return "#1e1e1e"
+}
+
+// pageForeground returns the color of text which doesn't set its own color
+func pageForeground(deck Deck) string {
+	if c := color(deck.Style.Document.Color); c != "" {
+		return c
+	}
+	if deck.Theme == "light" {
+		return "#000000"
+	}
+	return "#d0d0d0"
+}
+
+// drawPage returns the content stream of a page showing the lines of the
+// rendered slide, with the footer in the bottom right corner
+func drawPage(slide, footer string, background, foreground [3]float64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s rg 0 0 %.2f %.2f re f\n", rgbOp(background), pageWidth, pageHeight)
+
+	for row, line := range strings.Split(slide, "\n") {
+		y := pageHeight - margin - float64(row+1)*lineHeight
+		for _, r := range parseANSI(line) {
+			drawRun(&b, r, y, foreground)
+		}
+	}
+
+	if footer != "" {
+		x := pageWidth - margin - float64(len([]rune(footer)))*charWidth
+		fmt.Fprintf(&b, "%s rg BT /F1 %.0f Tf %.2f %.2f Td (%s) Tj ET\n", rgbOp(foreground), fontSize, x, margin, pdfText(footer))
+	}
+	return b.String()
+}
+
+// drawRun draws a run of text on the line at y, with its background
+func drawRun(b *strings.Builder, r run, y float64, foreground [3]float64) {
+	x := margin + float64(r.col)*charWidth
+	width := float64(len([]rune(r.text))) * charWidth
+	if r.bg != nil {
+		fmt.Fprintf(b, "%s rg %.2f %.2f %.2f %.2f re f\n", rgbOp(*r.bg), x, y-fontSize*0.25, width, lineHeight)
+	}
+	if strings.TrimSpace(r.text) == "" {
+		return
+	}
+	fg := foreground
+	if r.fg != nil {
+		fg = *r.fg
+	}
+	font := 1
+	if r.bold {
+		font++
+	}
+	if r.italic {
+		font += 2
+	}
+	fmt.Fprintf(b, "%s rg BT /F%d %.0f Tf %.2f %.2f Td (%s) Tj ET\n", rgbOp(fg), font, fontSize, x, y, pdfText(r.text))
+}
+
+// run is text drawn with the same style, starting at the column col
+type run struct {
+	col    int
+	text   string
+	fg, bg *[3]float64
+	bold   bool
+	italic bool
+}
+
+// parseANSI splits a line of the rendered slide into runs of text with the
+// same style, reading the colors and attributes of SGR escape sequences
+func parseANSI(line string) []run {
+	var (
+		runs    []run
+		current run
+		text    strings.Builder
+		col     int
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			current.text = text.String()
+			runs = append(runs, current)
+			text.Reset()
+		}
+		current.col = col
+	}
+
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' && i+1 < len(line) && line[i+1] == '[' {
+			end := i + 2
+			for end < len(line) && (line[end] < 0x40 || line[end] > 0x7e) {
+				end++
+			}
+			if end >= len(line) {
+				break
+			}
+			if line[end] == 'm' {
+				flush()
+				applySGR(&current, line[i+2:end])
+			}
+			i = end + 1
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		text.WriteRune(r)
+		col++
+		i += size
+	}
+	flush()
+	return runs
+}
+
+// applySGR updates the style of the run with the parameters of an SGR
+// escape sequence
+func applySGR(r *run, params string) {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, _ := strconv.Atoi(codes[i])
+		switch {
+		case code == 0:
+			*r = run{col: r.col}
+		case code == 1:
+			r.bold = true
+		case code == 3:
+			r.italic = true
+		case code == 22:
+			r.bold = false
+		case code == 23:
+			r.italic = false
+		case code == 39:
+			r.fg = nil
+		case code == 49:
+			r.bg = nil
+		case code >= 30 && code <= 37, code >= 90 && code <= 97, code >= 40 && code <= 47, code >= 100 && code <= 107:
+			n := code % 10
+			if code >= 90 {
+				n += 8
+			}
+			c := rgb(ansiColor(n))
+			if code >= 40 && code <= 47 || code >= 100 {
+				r.bg = &c
+			} else {
+				r.fg = &c
+			}
+		case code == 38 || code == 48:
+			var c [3]float64
+			if i+2 < len(codes) && codes[i+1] == "5" {
+				n, _ := strconv.Atoi(codes[i+2])
+				c = rgb(ansiColor(n))
+				i += 2
+			} else if i+4 < len(codes) && codes[i+1] == "2" {
+				for j := range c {
+					v, _ := strconv.Atoi(codes[i+2+j])
+					c[j] = float64(v) / 255
+				}
+				i += 4
+			} else {
+				continue
+			}
+			if code == 38 {
+				r.fg = &c
+			} else {
+				r.bg = &c
+			}
+		}
+	}
+}
+
+// ansiColor returns the hex color of an ANSI 256 color code
+func ansiColor(n int) string {
+	code := strconv.Itoa(n)
+	return color(&code)
+}
+
+// rgb parses a hex color into its components, between 0 and 1
+func rgb(hex string) [3]float64 {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	var c [3]float64
+	if len(hex) != 6 {
+		return c
+	}
+	for i := range c {
+		v, _ := strconv.ParseUint(hex[2*i:2*i+2], 16, 8)
+		c[i] = float64(v) / 255
+	}
+	return c
+}
+
+func rgbOp(c [3]float64) string {
+	return fmt.Sprintf("%.3f %.3f %.3f", c[0], c[1], c[2])
+}
+
+// pdfText escapes text for a PDF string in the WinAnsi encoding of the
+// standard fonts. Characters the encoding lacks are replaced, box drawing
+// characters by their closest ASCII equivalent.
+func pdfText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			if c, ok := winAnsi[r]; ok {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else if c, ok := boxDrawing[r]; ok {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
+
+var winAnsi = map[rune]byte{
+	'…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '€': 0x80, '™': 0x99,
+}
+
+var boxDrawing = map[rune]byte{
+	'│': '|', '┃': '|', '║': '|', '▌': '|', '▐': '|',
+	'─': '-', '━': '-', '═': '=',
+	'┌': '+', '┐': '+', '└': '+', '┘': '+', '├': '+', '┤': '+', '┬': '+', '┴': '+', '┼': '+',
+	'╭': '+', '╮': '+', '╰': '+', '╯': '+',
+	'█': '#', '░': '.', '▒': ':', '▓': '#',
+}
+
+// writePDF writes a PDF document of the pages, given their content streams
+func writePDF(pages []string) string {
+	var (
+		b       strings.Builder
+		offsets []int
+	)
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 and 2 are the catalog and the page tree, 3 to 6 are the
+	// fonts and every page is followed by its content stream
+	fonts := []string{"Courier", "Courier-Bold", "Courier-Oblique", "Courier-BoldOblique"}
+	first := 3 + len(fonts)
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", first+2*i))
+	}
+
+	b.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	var resources []string
+	for i, font := range fonts {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font))
+		resources = append(resources, fmt.Sprintf("/F%d %d 0 R", i+1, 3+i))
+	}
+	for i, content := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, strings.Join(resources, " "), first+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(offsets)+1, xref)
+	return b.String()
+}
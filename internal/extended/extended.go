@@ -0,0 +1,127 @@
+// Package extended implements rendering of extended markdown syntax which
+// glamour does not support: ==highlight==, H~2~O subscripts and x^2^
+// superscripts
+package extended
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/maaslalani/slides/internal/placeholder"
+)
+
+// Markers delimiting highlighted text, which are replaced with escape
+// sequences once the slide is rendered
+var (
+	HighlightStart = placeholder.Highlight.Start
+	HighlightEnd   = placeholder.Highlight.End
+)
+
+const (
+	reverse    = "\x1b[7m"
+	reverseOff = "\x1b[27m"
+	reset      = "\x1b[0m"
+)
+
+var (
+	reHighlight   = regexp.MustCompile(`==([^=\s](?:[^=]*[^=\s])?)==`)
+	reSubscript   = regexp.MustCompile(`(^|[^~])~([^~\s]+)~([^~]|$)`)
+	reSuperscript = regexp.MustCompile(`\^([^\^\s]+)\^`)
+)
+
+var subscripts = map[rune]rune{
+	'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆', '7': '₇', '8': '₈', '9': '₉',
+	'+': '₊', '-': '₋', '=': '₌', '(': '₍', ')': '₎',
+	'a': 'ₐ', 'e': 'ₑ', 'h': 'ₕ', 'i': 'ᵢ', 'j': 'ⱼ', 'k': 'ₖ', 'l': 'ₗ', 'm': 'ₘ', 'n': 'ₙ',
+	'o': 'ₒ', 'p': 'ₚ', 'r': 'ᵣ', 's': 'ₛ', 't': 'ₜ', 'u': 'ᵤ', 'v': 'ᵥ', 'x': 'ₓ',
+}
+
+var superscripts = map[rune]rune{
+	'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹',
+	'+': '⁺', '-': '⁻', '=': '⁼', '(': '⁽', ')': '⁾',
+	'a': 'ᵃ', 'b': 'ᵇ', 'c': 'ᶜ', 'd': 'ᵈ', 'e': 'ᵉ', 'f': 'ᶠ', 'g': 'ᵍ', 'h': 'ʰ', 'i': 'ⁱ',
+	'j': 'ʲ', 'k': 'ᵏ', 'l': 'ˡ', 'm': 'ᵐ', 'n': 'ⁿ', 'o': 'ᵒ', 'p': 'ᵖ', 'r': 'ʳ', 's': 'ˢ',
+	't': 'ᵗ', 'u': 'ᵘ', 'v': 'ᵛ', 'w': 'ʷ', 'x': 'ˣ', 'y': 'ʸ', 'z': 'ᶻ',
+}
+
+// Transform converts the extended syntax of the markdown, outside of code
+// blocks and code spans, before it is rendered. Subscripts and superscripts
+// are replaced with their unicode equivalents where possible and highlights
+// are wrapped in markers to be styled by Highlight.
+func Transform(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	var fence bool
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = !fence
+			continue
+		}
+		if fence {
+			continue
+		}
+
+		// Odd segments are within code spans
+		segments := strings.Split(line, "`")
+		for j := 0; j < len(segments); j += 2 {
+			segments[j] = transform(segments[j])
+		}
+		lines[i] = strings.Join(segments, "`")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func transform(text string) string {
+	text = reHighlight.ReplaceAllString(text, HighlightStart+"$1"+HighlightEnd)
+	text = reSuperscript.ReplaceAllStringFunc(text, func(match string) string {
+		return convert(match, reSuperscript.FindStringSubmatch(match)[1], superscripts)
+	})
+	return reSubscript.ReplaceAllStringFunc(text, func(match string) string {
+		m := reSubscript.FindStringSubmatch(match)
+		return m[1] + convert("~"+m[2]+"~", m[2], subscripts) + m[3]
+	})
+}
+
+// convert maps every character of the text, returning the original match if
+// any of the characters can't be mapped
+func convert(match, text string, chars map[rune]rune) string {
+	var b strings.Builder
+	for _, r := range text {
+		c, ok := chars[r]
+		if !ok {
+			return match
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// Highlight replaces the highlight markers of rendered content with reverse
+// video, which is re-applied after every reset within the highlighted text
+func Highlight(rendered string) string {
+	if !strings.Contains(rendered, HighlightStart) {
+		return rendered
+	}
+
+	var b strings.Builder
+	var highlighted bool
+	for len(rendered) > 0 {
+		switch {
+		case strings.HasPrefix(rendered, HighlightStart):
+			highlighted = true
+			b.WriteString(reverse)
+			rendered = rendered[len(HighlightStart):]
+		case strings.HasPrefix(rendered, HighlightEnd):
+			highlighted = false
+			b.WriteString(reverseOff)
+			rendered = rendered[len(HighlightEnd):]
+		case highlighted && strings.HasPrefix(rendered, reset):
+			b.WriteString(reset + reverse)
+			rendered = rendered[len(reset):]
+		default:
+			b.WriteByte(rendered[0])
+			rendered = rendered[1:]
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package extended_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/extended"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{name: "Subscript", markdown: "H~2~O", want: "H₂O"},
+		{name: "Superscript", markdown: "x^2^ + y^n+1^", want: "x² + yⁿ⁺¹"},
+		{name: "Unsupported characters", markdown: "x^Q^ H~Z~", want: "x^Q^ H~Z~"},
+		{name: "Strikethrough is untouched", markdown: "~~strike~~", want: "~~strike~~"},
+		{name: "Highlight", markdown: "an ==important== word", want: "an " + extended.HighlightStart + "important" + extended.HighlightEnd + " word"},
+		{name: "Code span", markdown: "`x^2^` x^2^", want: "`x^2^` x²"},
+		{name: "Code block", markdown: "```\nH~2~O\n```\nH~2~O", want: "```\nH~2~O\n```\nH₂O"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extended.Transform(tt.markdown))
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	rendered := "a " + extended.HighlightStart + "\x1b[1mb\x1b[0m c" + extended.HighlightEnd + " d\x1b[0m"
+	want := "a \x1b[7m\x1b[1mb\x1b[0m\x1b[7m c\x1b[27m d\x1b[0m"
+	assert.Equal(t, want, extended.Highlight(rendered))
+	assert.Equal(t, "plain", extended.Highlight("plain"))
+}
@@ -3,8 +3,15 @@
 package file
 
 import (
+	"fmt"
+	"io"
 	"io/fs"
+	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 // Exists is a helper to verify
@@ -22,3 +29,135 @@ func Exists(filepath string) bool {
 func IsExecutable(s fs.FileInfo) bool {
 	return s.Mode().Perm()&0111 == 0111
 }
+
+// IsWritable returns whether a file has write permissions for its owner
+func IsWritable(s fs.FileInfo) bool {
+	return s.Mode().Perm()&0200 == 0200
+}
+
+// Write replaces the contents of the file with data, keeping its
+// permissions. The data is written to a temporary file first so that the
+// file is never left partially written.
+func Write(path string, data []byte) error {
+	s, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".slides-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), s.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Markdown returns the paths of the markdown files in the directory, sorted
+// by their names
+func Markdown(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// ModTime returns when the file was last modified. The modification time of
+// a directory is the latest of the directory and its markdown files, so that
+// editing any of the files changes it.
+func ModTime(path string) (time.Time, error) {
+	s, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	latest := s.ModTime()
+	if !s.IsDir() {
+		return latest, nil
+	}
+	paths, err := Markdown(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, p := range paths {
+		s, err := os.Stat(p)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if s.ModTime().After(latest) {
+			latest = s.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// FetchTimeout is how long fetching a file from a URL may take
+const FetchTimeout = 10 * time.Second
+
+// IsURL returns whether the path is an HTTP or HTTPS URL rather than a path
+// on the filesystem
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// Fetch downloads the contents of the file at the URL
+func Fetch(url string) (string, error) {
+	client := http.Client{Timeout: FetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("could not fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not fetch %s: %s", url, resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("could not fetch %s: %w", url, err)
+	}
+	return string(b), nil
+}
+
+// Stream reads the reader as its content arrives, sending everything read so
+// far whenever more of it is read. The channel is closed once the reader is
+// read to the end.
+func Stream(r io.Reader) <-chan string {
+	contents := make(chan string)
+	go func() {
+		defer close(contents)
+		var b strings.Builder
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				b.Write(buf[:n])
+				contents <- b.String()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return contents
+}
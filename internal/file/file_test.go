@@ -2,9 +2,14 @@ package file_test
 
 import (
 	"fmt"
+	"io"
 	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/maaslalani/slides/internal/file"
 	"github.com/stretchr/testify/assert"
@@ -70,3 +75,146 @@ func TestIsExecutable(t *testing.T) {
 		})
 	}
 }
+
+func TestIsWritable(t *testing.T) {
+	tests := []struct {
+		perm     fs.FileMode
+		expected bool
+	}{
+		{0444, false},
+		{0644, true},
+		{0755, true},
+		{0555, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.perm), func(t *testing.T) {
+			tmp, err := os.CreateTemp(os.TempDir(), "slides-*")
+			if err != nil {
+				t.Fatal("failed to create temp file")
+			}
+			defer os.Remove(tmp.Name())
+
+			err = tmp.Chmod(tc.perm)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := tmp.Stat()
+			if err != nil {
+				t.Fatal("failed to stat file")
+			}
+
+			assert.Equal(t, tc.expected, file.IsWritable(s))
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tmp, err := os.CreateTemp(os.TempDir(), "slides-*")
+	if err != nil {
+		t.Fatal("failed to create temp file")
+	}
+	defer os.Remove(tmp.Name())
+
+	err = tmp.Chmod(0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp.Close()
+
+	err = file.Write(tmp.Name(), []byte("# Slide"))
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(tmp.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, "# Slide", string(b))
+
+	s, err := os.Stat(tmp.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0755), s.Mode().Perm())
+}
+
+func TestMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"02-middle.md", "01-intro.md", "notes.txt", "03-end.MD"} {
+		err := os.WriteFile(filepath.Join(dir, name), []byte("# Slide"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := os.Mkdir(filepath.Join(dir, "images.md"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := file.Markdown(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "01-intro.md"),
+		filepath.Join(dir, "02-middle.md"),
+		filepath.Join(dir, "03-end.MD"),
+	}, paths)
+}
+
+func TestModTime(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "slides.md")
+	err := os.WriteFile(path, []byte("# Slide"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Now().Add(time.Hour).Truncate(time.Second)
+	err = os.Chtimes(path, modified, modified)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := file.ModTime(path)
+	assert.NoError(t, err)
+	assert.True(t, modified.Equal(got))
+
+	got, err = file.ModTime(dir)
+	assert.NoError(t, err)
+	assert.True(t, modified.Equal(got))
+}
+
+func TestIsURL(t *testing.T) {
+	assert.True(t, file.IsURL("https://example.com/talk.md"))
+	assert.True(t, file.IsURL("http://example.com/talk.md"))
+	assert.False(t, file.IsURL("talk.md"))
+	assert.False(t, file.IsURL("http.md"))
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/talk.md" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "# Slide")
+	}))
+	defer server.Close()
+
+	content, err := file.Fetch(server.URL + "/talk.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "# Slide", content)
+
+	_, err = file.Fetch(server.URL + "/missing.md")
+	assert.EqualError(t, err, "could not fetch "+server.URL+"/missing.md: 404 Not Found")
+}
+
+func TestStream(t *testing.T) {
+	r, w := io.Pipe()
+	contents := file.Stream(r)
+
+	go w.Write([]byte("# One\n---\n"))
+	assert.Equal(t, "# One\n---\n", <-contents)
+
+	go w.Write([]byte("# Two\n"))
+	assert.Equal(t, "# One\n---\n# Two\n", <-contents)
+
+	w.Close()
+	_, ok := <-contents
+	assert.False(t, ok)
+}
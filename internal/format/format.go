@@ -0,0 +1,178 @@
+// Package format implements normalizing the source of slides, in the spirit
+// of gofmt
+package format
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/maaslalani/slides/internal/meta"
+)
+
+const (
+	delimiter = "\n---\n"
+	// documentEnd ends YAML front matter as an alternative to the delimiter
+	documentEnd = "\n...\n"
+)
+
+var reFence = regexp.MustCompile("^\\s*(```|~~~)")
+
+// Format normalizes the slides: line endings become \n, trailing whitespace
+// is trimmed (keeping hard line breaks), runs of blank lines collapse to one
+// outside of code blocks, slides are separated by a delimiter surrounded by
+// blank lines and the file ends with a single newline. Blank slides are kept
+// as they are. The slides are separated by the separator of the metadata,
+// if it sets one. Formatting formatted slides changes nothing.
+func Format(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+
+	// Only slides starting with a delimiter have front matter, which ends
+	// with a delimiter or the end of a YAML document
+	frontMatter := strings.HasPrefix(content, strings.TrimPrefix(delimiter, "\n"))
+	content = strings.TrimPrefix(content, strings.TrimPrefix(delimiter, "\n"))
+	parts := strings.SplitN(content, delimiter, 2)
+	end := delimiter
+	if i := strings.Index(content, documentEnd); frontMatter && i >= 0 && (len(parts) < 2 || i < len(parts[0])) {
+		parts = []string{content[:i], content[i+len(documentEnd):]}
+		end = documentEnd
+	}
+
+	var header string
+	separator := delimiter
+	if metaData, exists := meta.New().Parse(parts[0]); frontMatter && exists && len(parts) > 1 {
+		header = strings.Trim(trimLines(parts[0]), "\n")
+		content = parts[1]
+		if sep := strings.Trim(metaData.Separator, "\n"); sep != "" {
+			separator = "\n" + sep + "\n"
+		}
+	}
+	content = strings.TrimPrefix(content, strings.TrimPrefix(separator, "\n"))
+	slides := strings.Split(content, separator)
+
+	// Blank slides are kept, so that slides keep their numbers
+	formatted := make([]string, len(slides))
+	for i, slide := range slides {
+		if slide = strings.Trim(formatSlide(slide), "\n"); slide != "" {
+			formatted[i] = "\n" + slide + "\n"
+		}
+	}
+	body := strings.Join(formatted, separator)
+	if formatted[0] != "" {
+		body = strings.TrimPrefix(body, "\n")
+	}
+	if !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+
+	var b strings.Builder
+	if header != "" {
+		fmt.Fprintf(&b, "---\n%s%s\n", header, end)
+	}
+	b.WriteString(body)
+	return b.String()
+}
+
+// formatSlide trims the trailing whitespace of each line and collapses runs
+// of blank lines outside of code blocks
+func formatSlide(slide string) string {
+	lines := strings.Split(slide, "\n")
+	var (
+		out []string
+		// fence is the marker of the code block the line is in, code blocks
+		// are only closed by the marker they are opened with
+		fence string
+	)
+	for i, line := range lines {
+		if m := reFence.FindStringSubmatch(line); m != nil {
+			switch fence {
+			case "":
+				fence = m[1]
+			case m[1]:
+				fence = ""
+			}
+		}
+		trimmed := strings.TrimRight(line, " \t")
+		if fence != "" {
+			out = append(out, trimmed)
+			continue
+		}
+		// Two trailing spaces are a hard line break in markdown, as long as
+		// another line follows
+		if strings.HasSuffix(line, "  ") && trimmed != "" && i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+			trimmed += "  "
+		}
+		if trimmed == "" && len(out) > 0 && out[len(out)-1] == "" {
+			continue
+		}
+		out = append(out, trimmed)
+	}
+	return strings.Join(out, "\n")
+}
+
+// trimLines trims the trailing whitespace of each line
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff returns the lines which differ between before and after, prefixed with
+// - for removed lines and + for added lines, in the style of a unified diff
+// without context. It is empty when there are no differences.
+func Diff(name, before, after string) string {
+	if before == after {
+		return ""
+	}
+	a := strings.Split(before, "\n")
+	b := strings.Split(after, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and
+	// b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s (formatted)\n", name, name)
+	var (
+		i, j   int
+		change bool
+	)
+	for i < len(a) || j < len(b) {
+		if i < len(a) && j < len(b) && a[i] == b[j] {
+			i++
+			j++
+			change = false
+			continue
+		}
+		// Every block of changes starts with the lines it changes
+		if !change {
+			fmt.Fprintf(&out, "@@ -%d +%d @@\n", i+1, j+1)
+			change = true
+		}
+		if i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]) {
+			fmt.Fprintf(&out, "-%s\n", a[i])
+			i++
+		} else {
+			fmt.Fprintf(&out, "+%s\n", b[j])
+			j++
+		}
+	}
+	return out.String()
+}
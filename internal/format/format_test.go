@@ -0,0 +1,76 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "Delimiter spacing",
+			content: "# One\n---\n# Two\n\n\n\n---\n\n# Three",
+			want:    "# One\n\n---\n\n# Two\n\n---\n\n# Three\n",
+		},
+		{
+			name:    "Trailing whitespace and line endings",
+			content: "# One  \r\nline one  \r\nline two \t\r\n",
+			want:    "# One  \nline one  \nline two\n",
+		},
+		{
+			name:    "Header",
+			content: "---\ntheme: dark \nauthor: Gopher\n---\n\n\n# One\n",
+			want:    "---\ntheme: dark\nauthor: Gopher\n---\n\n# One\n",
+		},
+		{
+			name:    "Blank lines in code blocks",
+			content: "```go\nfunc main() {\n\n\n}\n```\n\n\n\ntext",
+			want:    "```go\nfunc main() {\n\n\n}\n```\n\ntext\n",
+		},
+		{
+			name:    "Fences within code blocks",
+			content: "~~~md\n```\n\n\n\nx\n```\n~~~",
+			want:    "~~~md\n```\n\n\n\nx\n```\n~~~\n",
+		},
+		{
+			name:    "Blank slides",
+			content: "# One\n---\n\n  \n---\n# Two\n---\n",
+			want:    "# One\n\n---\n\n---\n\n# Two\n\n---\n",
+		},
+		{
+			name:    "Separator",
+			content: "---\nseparator: \"===\"\n---\n# One\n\nSubtitle\n---\n===\n# Two",
+			want:    "---\nseparator: \"===\"\n---\n\n# One\n\nSubtitle\n---\n\n===\n\n# Two\n",
+		},
+		{
+			name:    "End of YAML document",
+			content: "---\nseparator: \"===\"\n...\n# One\n===\n# Two",
+			want:    "---\nseparator: \"===\"\n...\n\n# One\n\n===\n\n# Two\n",
+		},
+		{
+			name:    "Blank first slide",
+			content: "\n---\n# Two",
+			want:    "\n---\n\n# Two\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := format.Format(tt.content)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, got, format.Format(got), "formatting should be idempotent")
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	assert.Equal(t, "", format.Diff("slides.md", "same\n", "same\n"))
+
+	want := "--- slides.md\n+++ slides.md (formatted)\n@@ -2 +2 @@\n-b \n+b\n+\n"
+	assert.Equal(t, want, format.Diff("slides.md", "a\nb \nc", "a\nb\n\nc"))
+}
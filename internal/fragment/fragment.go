@@ -0,0 +1,51 @@
+// Package fragment implements revealing a slide one fragment at a time,
+// fragments are separated by a line containing only +++
+package fragment
+
+import "strings"
+
+// Marker is the line separating the fragments of a slide
+const Marker = "+++"
+
+// split splits the slide into its fragments, markers inside of code blocks
+// don't separate fragments
+func split(slide string) []string {
+	var (
+		fragments []string
+		current   []string
+		fence     bool
+	)
+	for _, line := range strings.Split(slide, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = !fence
+		}
+		if !fence && trimmed == Marker {
+			fragments = append(fragments, strings.Join(current, "\n"))
+			current = nil
+			continue
+		}
+		current = append(current, line)
+	}
+	return append(fragments, strings.Join(current, "\n"))
+}
+
+// Count returns the number of fragments of the slide, slides without
+// markers have a single fragment
+func Count(slide string) int {
+	return len(split(slide))
+}
+
+// Reveal returns the slide up to and including the fragment n, counting from
+// zero, without the markers. The whole slide is returned if n is past its
+// last fragment.
+func Reveal(slide string, n int) string {
+	fragments := split(slide)
+	if n >= len(fragments) {
+		n = len(fragments) - 1
+	}
+	if n < 0 {
+		n = 0
+	}
+	return strings.Join(fragments[:n+1], "\n")
+}
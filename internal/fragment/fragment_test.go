@@ -0,0 +1,35 @@
+package fragment_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/fragment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCount(t *testing.T) {
+	assert.Equal(t, 1, fragment.Count("# Slide\n\n* one"))
+	assert.Equal(t, 3, fragment.Count("# Slide\n\n* one\n+++\n* two\n  +++  \n* three"))
+	assert.Equal(t, 1, fragment.Count("```\n+++\n```"))
+}
+
+func TestReveal(t *testing.T) {
+	slide := "# Slide\n* one\n+++\n* two\n+++\n* three"
+	tests := []struct {
+		name string
+		n    int
+		want string
+	}{
+		{name: "First fragment", n: 0, want: "# Slide\n* one"},
+		{name: "Second fragment", n: 1, want: "# Slide\n* one\n* two"},
+		{name: "Last fragment", n: 2, want: "# Slide\n* one\n* two\n* three"},
+		{name: "Past last fragment", n: 5, want: "# Slide\n* one\n* two\n* three"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fragment.Reveal(slide, tt.n))
+		})
+	}
+
+	assert.Equal(t, "```\n+++\n```", fragment.Reveal("```\n+++\n```", 0))
+}
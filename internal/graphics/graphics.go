@@ -0,0 +1,253 @@
+// Package graphics implements drawing images in terminals which support
+// a graphics protocol
+package graphics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// Protocol is a terminal graphics protocol
+type Protocol int
+
+const (
+	// None means the terminal can not display images
+	None Protocol = iota
+	// Kitty is the kitty terminal graphics protocol
+	Kitty
+	// ITerm is the iTerm2 inline images protocol
+	ITerm
+	// Sixel is the DEC sixel graphics format
+	Sixel
+)
+
+const (
+	// cellWidth and cellHeight are the assumed size of a terminal cell in
+	// pixels, sixel images are sized in pixels rather than cells
+	cellWidth  = 10
+	cellHeight = 20
+)
+
+// chunkSize is the maximum size of a kitty graphics protocol payload
+const chunkSize = 4096
+
+// Detect returns the graphics protocol supported by the current terminal
+func Detect() Protocol {
+	return detect(os.Getenv)
+}
+
+func detect(getenv func(string) string) Protocol {
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", strings.Contains(getenv("TERM"), "kitty"):
+		return Kitty
+	case getenv("TERM_PROGRAM") == "iTerm.app", getenv("TERM_PROGRAM") == "WezTerm":
+		return ITerm
+	case strings.Contains(getenv("TERM"), "sixel"), strings.HasPrefix(getenv("TERM"), "foot"),
+		strings.HasPrefix(getenv("TERM"), "mlterm"):
+		return Sixel
+	default:
+		return None
+	}
+}
+
+// Load reads and decodes the image at the given path
+func Load(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// Dim returns a copy of the image with every pixel darkened by amount, where
+// 0 leaves the image untouched and 1 turns the image black
+func Dim(img image.Image, amount float64) image.Image {
+	bounds := img.Bounds()
+	dimmed := image.NewRGBA(bounds)
+	draw.Draw(dimmed, bounds, img, bounds.Min, draw.Src)
+
+	keep := 1 - amount
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := dimmed.RGBAAt(x, y)
+			dimmed.SetRGBA(x, y, color.RGBA{
+				R: uint8(float64(c.R) * keep),
+				G: uint8(float64(c.G) * keep),
+				B: uint8(float64(c.B) * keep),
+				A: c.A,
+			})
+		}
+	}
+	return dimmed
+}
+
+// Encode returns the escape sequence which draws the image with the given
+// protocol at the cursor, scaled to fill cols by rows cells. A negative z
+// draws the image beneath the text, which is only supported by kitty.
+func Encode(p Protocol, img image.Image, cols, rows, z int) (string, error) {
+	if p == Sixel {
+		return encodeSixel(resize(img, cols*cellWidth, rows*cellHeight)), nil
+	}
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		return "", err
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	switch p {
+	case Kitty:
+		return encodeKitty(data, cols, rows, z), nil
+	case ITerm:
+		return fmt.Sprintf("\x1b]1337;File=inline=1;width=%d;height=%d;preserveAspectRatio=0:%s\a", cols, rows, data), nil
+	default:
+		return "", nil
+	}
+}
+
+func encodeKitty(data string, cols, rows, z int) string {
+	var b strings.Builder
+	first := true
+	for len(data) > 0 {
+		n := chunkSize
+		if len(data) < n {
+			n = len(data)
+		}
+		chunk := data[:n]
+		data = data[n:]
+
+		more := 0
+		if len(data) > 0 {
+			more = 1
+		}
+
+		if first {
+			// C=1 keeps the cursor in place after drawing
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,z=%d,m=%d;%s\x1b\\", cols, rows, z, more, chunk)
+			first = false
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String()
+}
+
+// resize scales the image to width by height pixels
+func resize(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/width
+			sy := bounds.Min.Y + y*bounds.Dy()/height
+			resized.Set(x, y, img.At(sx, sy))
+		}
+	}
+	return resized
+}
+
+// encodeSixel encodes the image as sixels, with its colors reduced to a
+// palette of 6 levels of red, green and blue
+func encodeSixel(img image.Image) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// The palette index of every pixel, transparent pixels are -1
+	pixels := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			if a < 0x8000 {
+				pixels[y*width+x] = -1
+				continue
+			}
+			pixels[y*width+x] = int(r*5/0xffff)*36 + int(g*5/0xffff)*6 + int(b*5/0xffff)
+		}
+	}
+
+	var s strings.Builder
+	// P2=1 leaves transparent pixels untouched
+	fmt.Fprintf(&s, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&s, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+	}
+
+	for top := 0; top < height; top += 6 {
+		// Draw each color of the band of six rows in turn, returning to
+		// the start of the band in between
+		var used []int
+		seen := map[int]bool{}
+		for y := top; y < top+6 && y < height; y++ {
+			for x := 0; x < width; x++ {
+				if c := pixels[y*width+x]; c >= 0 && !seen[c] {
+					seen[c] = true
+					used = append(used, c)
+				}
+			}
+		}
+		for i, c := range used {
+			if i > 0 {
+				s.WriteByte('$')
+			}
+			fmt.Fprintf(&s, "#%d", c)
+			var run byte
+			count := 0
+			flush := func() {
+				switch {
+				case count == 0:
+				case count > 3:
+					fmt.Fprintf(&s, "!%d%c", count, run)
+				default:
+					s.WriteString(strings.Repeat(string(run), count))
+				}
+			}
+			for x := 0; x < width; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && top+dy < height; dy++ {
+					if pixels[(top+dy)*width+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				char := 63 + bits
+				if count > 0 && char != run {
+					flush()
+					count = 0
+				}
+				run = char
+				count++
+			}
+			flush()
+		}
+		s.WriteByte('-')
+	}
+	s.WriteString("\x1b\\")
+	return s.String()
+}
+
+// Clear returns the escape sequence removing all images drawn with the
+// protocol, only kitty supports removing images
+func Clear(p Protocol) string {
+	if p == Kitty {
+		return "\x1b_Ga=d,q=2\x1b\\"
+	}
+	return ""
+}
+
+// Place wraps the escape sequence so that it is drawn at the given zero
+// based row and column, restoring the cursor position afterwards
+func Place(row, col int, seq string) string {
+	return fmt.Sprintf("\x1b7\x1b[%d;%dH%s\x1b8", row+1, col+1, seq)
+}
@@ -0,0 +1,82 @@
+package graphics
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Protocol
+	}{
+		{name: "kitty window", env: map[string]string{"KITTY_WINDOW_ID": "1"}, want: Kitty},
+		{name: "kitty term", env: map[string]string{"TERM": "xterm-kitty"}, want: Kitty},
+		{name: "iterm", env: map[string]string{"TERM_PROGRAM": "iTerm.app"}, want: ITerm},
+		{name: "wezterm", env: map[string]string{"TERM_PROGRAM": "WezTerm"}, want: ITerm},
+		{name: "sixel term", env: map[string]string{"TERM": "xterm-sixel"}, want: Sixel},
+		{name: "foot", env: map[string]string{"TERM": "foot"}, want: Sixel},
+		{name: "unsupported", env: map[string]string{"TERM": "xterm-256color"}, want: None},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detect(func(key string) string { return tt.env[key] })
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDim(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.SetRGBA(0, 0, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+
+	got := Dim(img, 0.5).(*image.RGBA).RGBAAt(0, 0)
+	assert.Equal(t, color.RGBA{R: 100, G: 50, B: 25, A: 255}, got)
+}
+
+func TestEncode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+
+	seq, err := Encode(Kitty, img, 10, 5, -1)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(seq, "\x1b_Ga=T,f=100,q=2,C=1,c=10,r=5,z=-1,m=0;"))
+
+	seq, err = Encode(ITerm, img, 10, 5, 0)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(seq, "\x1b]1337;File=inline=1;width=10;height=5;"))
+
+	seq, err = Encode(None, img, 10, 5, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, seq)
+}
+
+func TestEncodeSixel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.SetRGBA(0, 0, color.RGBA{R: 255, A: 255})
+	img.SetRGBA(1, 0, color.RGBA{R: 255, A: 255})
+	img.SetRGBA(0, 1, color.RGBA{B: 255, A: 255})
+
+	seq := encodeSixel(img)
+	assert.True(t, strings.HasPrefix(seq, "\x1bP0;1;0q\"1;1;2;2#0;2;0;0;0"))
+	assert.True(t, strings.HasSuffix(seq, "\x1b\\"))
+	// Red fills the top row, blue the bottom left pixel and the bottom right
+	// pixel is transparent
+	assert.Contains(t, seq, "#215;2;100;100;100#180@@$#5A?-")
+
+	seq, err := Encode(Sixel, img, 1, 1, 0)
+	assert.NoError(t, err)
+	assert.Contains(t, seq, "\"1;1;10;20")
+}
+
+func TestEncodeKittyChunks(t *testing.T) {
+	data := strings.Repeat("a", chunkSize+10)
+	seq := encodeKitty(data, 1, 1, 0)
+	assert.Equal(t, 2, strings.Count(seq, "\x1b_G"))
+	assert.Contains(t, seq, ",m=1;")
+	assert.Contains(t, seq, "\x1b_Gm=0;aaaaaaaaaa\x1b\\")
+}
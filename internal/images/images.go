@@ -0,0 +1,116 @@
+// Package images implements rendering the images of a slide which are on a
+// line of their own, so that they can be drawn with a terminal graphics
+// protocol
+package images
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maaslalani/slides/internal/placeholder"
+)
+
+// reImage matches an image on a line of its own, with an optional title
+var reImage = regexp.MustCompile(`^\s*!\[([^\]]*)\]\(\s*(\S+?)(?:\s+"[^"]*")?\s*\)\s*$`)
+
+var (
+	// areaStyle fills the space an image is drawn in, so that the lines of
+	// the image differ from blank lines and are redrawn when the slide
+	// changes, erasing the image
+	areaStyle        = lipgloss.NewStyle().Background(lipgloss.Color("#3C3C3C")).Foreground(lipgloss.Color("#7F7F7F"))
+	placeholderStyle = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#7F7F7F")).Padding(0, 1).Faint(true)
+)
+
+// Image is an image of a slide
+type Image struct {
+	Alt  string
+	Path string
+}
+
+// label returns the text shown in place of the image
+func (i Image) label() string {
+	if i.Alt != "" {
+		return i.Alt
+	}
+	return i.Path
+}
+
+// Placement is where an image is drawn on the rendered slide, in cells
+// counting from zero
+type Placement struct {
+	Image
+	Line int
+	Col  int
+	Cols int
+	Rows int
+}
+
+// Extract replaces the images of the markdown which are on a line of their
+// own, outside of code blocks, with placeholders and returns the images
+func Extract(markdown string) (string, []Image) {
+	var (
+		lines  = strings.Split(markdown, "\n")
+		images []Image
+		fence  bool
+	)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = !fence
+			continue
+		}
+		if fence {
+			continue
+		}
+		match := reImage.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		lines[i] = placeholder.Image.Mark(len(images))
+		images = append(images, Image{Alt: match[1], Path: match[2]})
+	}
+	return strings.Join(lines, "\n"), images
+}
+
+// Restore replaces the lines of the rendered slide containing placeholders,
+// keeping the indentation of the line. Images are given the space returned
+// by size to be drawn in, images which can't be drawn are replaced with a
+// box showing their alt text instead.
+func Restore(rendered string, images []Image, size func(Image) (cols, rows int, ok bool)) (string, []Placement) {
+	var (
+		lines      []string
+		placements []Placement
+	)
+	for _, line := range strings.Split(rendered, "\n") {
+		n, col, ok := placeholder.Image.Find(line)
+		if !ok || n >= len(images) {
+			lines = append(lines, line)
+			continue
+		}
+
+		img := images[n]
+		indent := strings.Repeat(" ", col)
+		cols, rows, ok := size(img)
+		if !ok || cols <= 0 || rows <= 0 {
+			for _, l := range strings.Split(placeholderStyle.Render("▣ "+img.label()), "\n") {
+				lines = append(lines, indent+l)
+			}
+			continue
+		}
+
+		placements = append(placements, Placement{Image: img, Line: len(lines), Col: col, Cols: cols, Rows: rows})
+		label := []rune(img.label())
+		if len(label) > cols {
+			label = label[:cols]
+		}
+		for row := 0; row < rows; row++ {
+			text := ""
+			if row == 0 {
+				text = string(label)
+			}
+			lines = append(lines, indent+areaStyle.Copy().Width(cols).Render(text))
+		}
+	}
+	return strings.Join(lines, "\n"), placements
+}
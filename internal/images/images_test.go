@@ -0,0 +1,41 @@
+package images_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maaslalani/slides/internal/images"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	markdown := "# Diagram\n\n![Architecture](arch.png)\n\nSee ![inline](x.png) here\n\n```\n![](code.png)\n```\n  ![](other.png \"title\")"
+	got, imgs := images.Extract(markdown)
+
+	assert.Equal(t, []images.Image{{Alt: "Architecture", Path: "arch.png"}, {Path: "other.png"}}, imgs)
+	assert.Equal(t, "# Diagram\n\n\uE0060\uE007\n\nSee ![inline](x.png) here\n\n```\n![](code.png)\n```\n\uE0061\uE007", got)
+}
+
+func TestRestore(t *testing.T) {
+	imgs := []images.Image{{Alt: "Architecture", Path: "arch.png"}, {Path: "missing.png"}}
+	rendered := "Title\n  \uE0060\uE007  \n\uE0061\uE007\nEnd"
+
+	got, placements := images.Restore(rendered, imgs, func(img images.Image) (int, int, bool) {
+		return 20, 3, img.Path == "arch.png"
+	})
+
+	lines := strings.Split(got, "\n")
+	// The title, three rows for the image, three for the placeholder box of
+	// the missing image and the last line
+	assert.Len(t, lines, 8)
+	assert.Equal(t, "Title", lines[0])
+	assert.Contains(t, lines[1], "Architecture")
+	assert.Equal(t, 22, lipgloss.Width(lines[2]))
+	assert.Contains(t, lines[5], "▣ missing.png")
+	assert.Equal(t, "End", lines[7])
+
+	assert.Equal(t, []images.Placement{
+		{Image: imgs[0], Line: 1, Col: 2, Cols: 20, Rows: 3},
+	}, placements)
+}
@@ -0,0 +1,160 @@
+package launcher
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maaslalani/slides/internal/model"
+	"github.com/maaslalani/slides/styles"
+)
+
+// Model is a menu listing the decks of a manifest, the selected deck is
+// presented and quitting it returns to the menu
+type Model struct {
+	Manifest Manifest
+	// NewDeck creates the presentation for a deck file
+	NewDeck func(fileName string) model.Model
+	cursor  int
+	deck    *model.Model
+	// launched counts the decks launched, it tells the messages of the deck
+	// being presented from those of decks which were quit
+	launched int
+	size     *tea.WindowSizeMsg
+	err      error
+}
+
+// New creates a launcher for the given manifest
+func New(manifest Manifest, newDeck func(fileName string) model.Model) Model {
+	return Model{Manifest: manifest, NewDeck: newDeck}
+}
+
+// menuMsg is sent when the deck being presented quits, to return to the menu
+type menuMsg struct{}
+
+// deckMsg is a message of the deck launched as the given number, such as the
+// ticks it schedules, which are dropped once the deck is quit
+type deckMsg struct {
+	deck int
+	msg  tea.Msg
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.size = &msg
+	}
+
+	if msg, ok := msg.(deckMsg); ok {
+		if m.deck == nil || msg.deck != m.launched {
+			return m, nil
+		}
+		if _, ok := msg.msg.(menuMsg); ok {
+			m.deck = nil
+			return m, nil
+		}
+		return m.updateDeck(msg.msg)
+	}
+	if m.deck != nil {
+		return m.updateDeck(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.Manifest.Decks)-1 {
+				m.cursor++
+			}
+		case "enter", " ":
+			return m.launch()
+		}
+	}
+	return m, nil
+}
+
+func (m Model) launch() (tea.Model, tea.Cmd) {
+	deck := m.NewDeck(m.Manifest.Decks[m.cursor].File)
+	// Quitting the deck returns to the menu instead of quitting slides
+	deck.QuitCmd = func() tea.Msg {
+		return menuMsg{}
+	}
+	m.err = deck.Load()
+	if m.err != nil {
+		return m, nil
+	}
+
+	m.launched++
+	var cmds []tea.Cmd
+	cmds = append(cmds, wrap(m.launched, deck.Init()))
+	if m.size != nil {
+		d, cmd := deck.Update(*m.size)
+		deck = d.(model.Model)
+		cmds = append(cmds, wrap(m.launched, cmd))
+	}
+	m.deck = &deck
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) updateDeck(msg tea.Msg) (tea.Model, tea.Cmd) {
+	d, cmd := m.deck.Update(msg)
+	deck := d.(model.Model)
+	m.deck = &deck
+	return m, wrap(m.launched, cmd)
+}
+
+// wrap tags the messages of the command with the deck they belong to,
+// including those of batched commands. Quitting isn't tagged, so that it
+// still quits.
+func wrap(deck int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		if msg == nil || msg == tea.Quit() {
+			return msg
+		}
+		// Batched commands are a list of commands, which bubbletea runs
+		// itself
+		if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice && v.Type().Elem() == reflect.TypeOf(tea.Cmd(nil)) {
+			cmds := make([]tea.Cmd, v.Len())
+			for i := range cmds {
+				cmds[i] = wrap(deck, v.Index(i).Interface().(tea.Cmd))
+			}
+			return tea.Batch(cmds...)()
+		}
+		return deckMsg{deck: deck, msg: msg}
+	}
+}
+
+func (m Model) View() string {
+	if m.deck != nil {
+		return m.deck.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Author.Render("Select a deck to present") + "\n\n")
+	for i, deck := range m.Manifest.Decks {
+		line := fmt.Sprintf("%s (%s)", deck.Title, deck.File)
+		if i == m.cursor {
+			b.WriteString(styles.Selected.Render("> "+line) + "\n")
+		} else {
+			b.WriteString(styles.Unselected.Render("  "+line) + "\n")
+		}
+	}
+	if m.err != nil {
+		b.WriteString("\n" + styles.Unselected.Render("Error: "+m.err.Error()) + "\n")
+	}
+	return styles.Slide.Render(b.String())
+}
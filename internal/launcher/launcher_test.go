@@ -0,0 +1,47 @@
+package launcher_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maaslalani/slides/internal/launcher"
+	"github.com/maaslalani/slides/internal/model"
+	"github.com/maaslalani/slides/internal/navigation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_quitDeck(t *testing.T) {
+	deck := filepath.Join(t.TempDir(), "deck.md")
+	if err := os.WriteFile(deck, []byte("# Deck\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifest := launcher.Manifest{Decks: []launcher.Deck{{Title: "Deck", File: deck}}}
+	var m tea.Model = launcher.New(manifest, func(fileName string) model.Model {
+		return model.Model{FileName: fileName, Search: navigation.NewSearch()}
+	})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, strings.Contains(m.View(), "Select a deck"))
+
+	// Quitting the deck returns to the menu rather than quitting
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatal("quitting the deck returned no command")
+	}
+	quit := cmd()
+	m, _ = m.Update(quit)
+	assert.True(t, strings.Contains(m.View(), "Select a deck"))
+
+	// Messages of the deck which was quit are dropped once another deck is
+	// launched
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(quit)
+	assert.False(t, strings.Contains(m.View(), "Select a deck"))
+
+	// ctrl+c quits slides rather than returning to the menu
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	assert.Equal(t, tea.Quit(), cmd())
+}
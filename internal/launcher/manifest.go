@@ -0,0 +1,74 @@
+// Package launcher implements a menu for picking which deck to present out of
+// the decks listed in a manifest file
+package launcher
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Deck is a single presentation listed in a manifest
+type Deck struct {
+	Title string `yaml:"title"`
+	File  string `yaml:"file"`
+}
+
+// Manifest lists all of the decks that can be presented
+type Manifest struct {
+	Decks []Deck `yaml:"decks"`
+}
+
+var (
+	ErrNoDecks = errors.New("manifest does not list any decks")
+)
+
+// IsManifest returns whether the given file name refers to a manifest rather
+// than a markdown deck
+func IsManifest(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// ParseManifest parses a manifest, relative deck paths are resolved against
+// dir. Decks without a title are titled with their file name.
+func ParseManifest(content []byte, dir string) (Manifest, error) {
+	var manifest Manifest
+	err := yaml.Unmarshal(content, &manifest)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var decks []Deck
+	for _, deck := range manifest.Decks {
+		if deck.File == "" {
+			continue
+		}
+		if !filepath.IsAbs(deck.File) {
+			deck.File = filepath.Join(dir, deck.File)
+		}
+		if deck.Title == "" {
+			deck.Title = filepath.Base(deck.File)
+		}
+		decks = append(decks, deck)
+	}
+
+	if len(decks) == 0 {
+		return Manifest{}, ErrNoDecks
+	}
+
+	manifest.Decks = decks
+	return manifest, nil
+}
+
+// ReadManifest reads and parses the manifest at the given path
+func ReadManifest(path string) (Manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, errors.New("could not read manifest")
+	}
+	return ParseManifest(b, filepath.Dir(path))
+}
@@ -0,0 +1,60 @@
+package launcher_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/maaslalani/slides/internal/launcher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsManifest(t *testing.T) {
+	assert.True(t, launcher.IsManifest("talks.yaml"))
+	assert.True(t, launcher.IsManifest("talks.YML"))
+	assert.False(t, launcher.IsManifest("slides.md"))
+	assert.False(t, launcher.IsManifest(""))
+}
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    launcher.Manifest
+		wantErr bool
+	}{
+		{
+			name: "Parse decks",
+			content: `
+decks:
+  - title: Introduction
+    file: intro.md
+  - file: /abs/advanced.md
+`,
+			want: launcher.Manifest{Decks: []launcher.Deck{
+				{Title: "Introduction", File: filepath.Join("talks", "intro.md")},
+				{Title: "advanced.md", File: "/abs/advanced.md"},
+			}},
+		},
+		{
+			name:    "Skip decks without files",
+			content: "decks:\n  - title: Missing\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid yaml",
+			content: "decks: [",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := launcher.ParseManifest([]byte(tt.content), "talks")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
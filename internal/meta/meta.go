@@ -3,8 +3,12 @@
 package meta
 
 import (
+	"errors"
+	"os"
 	"os/user"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -13,19 +17,117 @@ import (
 // from values set to empty strings in the YAML header. We replace values not
 // set by defaults values when parsing a header.
 type parsedMeta struct {
-	Theme  *string `yaml:"theme"`
-	Author *string `yaml:"author"`
-	Date   *string `yaml:"date"`
-	Paging *string `yaml:"paging"`
+	Theme             *string              `yaml:"theme"`
+	Author            *string              `yaml:"author"`
+	Date              *string              `yaml:"date"`
+	Paging            *string              `yaml:"paging"`
+	Progress          *string              `yaml:"progress"`
+	TabWidth          *int                 `yaml:"tabWidth"`
+	Background        *string              `yaml:"background"`
+	Ascii             *bool                `yaml:"ascii"`
+	Templates         *bool                `yaml:"templates"`
+	AllowExec         *bool                `yaml:"allowExec"`
+	AutoPaginate      *bool                `yaml:"autoPaginate"`
+	SearchPrompt      *string              `yaml:"searchPrompt"`
+	SearchPlaceholder *string              `yaml:"searchPlaceholder"`
+	SearchColor       *string              `yaml:"searchColor"`
+	Watch             *bool                `yaml:"watch"`
+	ExtendedSyntax    *bool                `yaml:"extendedSyntax"`
+	Tree              *bool                `yaml:"tree"`
+	QuotePanels       *bool                `yaml:"quotePanels"`
+	IntroDelay        *time.Duration       `yaml:"introDelay"`
+	Contact           *string              `yaml:"contact"`
+	Email             *string              `yaml:"email"`
+	Social            *string              `yaml:"social"`
+	TypeTarget        *string              `yaml:"typeTarget"`
+	TypeDelay         *time.Duration       `yaml:"typeDelay"`
+	Canvas            *string              `yaml:"canvas"`
+	Transition        *string              `yaml:"transition"`
+	TransitionScope   *string              `yaml:"transitionScope"`
+	SlideFilter       *string              `yaml:"slideFilter"`
+	Timer             *bool                `yaml:"timer"`
+	Duration          *time.Duration       `yaml:"duration"`
+	CodeRunners       *map[string]string   `yaml:"codeRunners"`
+	CodeTimeout       *time.Duration       `yaml:"codeTimeout"`
+	CodeLog           *string              `yaml:"codeLog"`
+	AutoAdvance       *time.Duration       `yaml:"autoAdvance"`
+	Vars              *map[string]string   `yaml:"vars"`
+	Header            *string              `yaml:"header"`
+	Footer            *string              `yaml:"footer"`
+	HideChrome        *bool                `yaml:"hideChrome"`
+	Keys              *map[string][]string `yaml:"keys"`
+	CodeTheme         *string              `yaml:"codeTheme"`
+	WordWrap          *int                 `yaml:"wordWrap"`
+	Align             *string              `yaml:"align"`
+	CodeDir           *string              `yaml:"codeDir"`
+	CodeEnv           *map[string]string   `yaml:"codeEnv"`
+	Separator         *string              `yaml:"separator"`
+	Padding           *string              `yaml:"padding"`
+	WatchInterval     *time.Duration       `yaml:"watchInterval"`
+	OnSlide           *string              `yaml:"onSlide"`
+	Fit               *bool                `yaml:"fit"`
+	ConfirmQuit       *bool                `yaml:"confirmQuit"`
+	WordsPerMinute    *int                 `yaml:"wordsPerMinute"`
+	SaveBookmarks     *bool                `yaml:"saveBookmarks"`
+	Diagrams          *bool                `yaml:"diagrams"`
 }
 
 // Meta contains all of the data to be parsed
 // out of a markdown file's header section
 type Meta struct {
-	Theme  string
-	Author string
-	Date   string
-	Paging string
+	Theme             string
+	Author            string
+	Date              string
+	Paging            string
+	Progress          string
+	TabWidth          int
+	Background        string
+	Ascii             bool
+	Templates         bool
+	AllowExec         bool
+	AutoPaginate      bool
+	SearchPrompt      string
+	SearchPlaceholder string
+	SearchColor       string
+	Watch             bool
+	ExtendedSyntax    bool
+	Tree              bool
+	QuotePanels       bool
+	IntroDelay        time.Duration
+	Contact           string
+	Email             string
+	Social            string
+	TypeTarget        string
+	TypeDelay         time.Duration
+	Canvas            string
+	Transition        string
+	TransitionScope   string
+	SlideFilter       string
+	Timer             bool
+	Duration          time.Duration
+	CodeRunners       map[string]string
+	CodeTimeout       time.Duration
+	CodeLog           string
+	AutoAdvance       time.Duration
+	Vars              map[string]string
+	Header            string
+	Footer            string
+	HideChrome        bool
+	Keys              map[string][]string
+	CodeTheme         string
+	WordWrap          int
+	Align             string
+	CodeDir           string
+	CodeEnv           map[string]string
+	Separator         string
+	Padding           string
+	WatchInterval     time.Duration
+	OnSlide           string
+	Fit               bool
+	ConfirmQuit       bool
+	WordsPerMinute    int
+	SaveBookmarks     bool
+	Diagrams          bool
 }
 
 // New creates a new instance of the
@@ -45,11 +147,23 @@ func (m *Meta) Parse(header string) (*Meta, bool) {
 		Author: defaultAuthor(),
 		Date:   defaultDate(),
 		Paging: defaultPaging(),
+		Watch:  defaultWatch(),
 	}
 
+	// Only a mapping is metadata, since a first slide such as a heading is
+	// valid YAML too
+	var mapping map[interface{}]interface{}
+	if err := yaml.Unmarshal([]byte(header), &mapping); err != nil || len(mapping) == 0 {
+		return fallback, false
+	}
+
+	// Front matter written for other tools may set options to values of a
+	// different type, such as a list of authors, which are left unset while
+	// the other options are still parsed
 	var tmp parsedMeta
 	err := yaml.Unmarshal([]byte(header), &tmp)
-	if err != nil {
+	var typeErr *yaml.TypeError
+	if err != nil && !errors.As(err, &typeErr) {
 		return fallback, false
 	}
 
@@ -77,11 +191,327 @@ func (m *Meta) Parse(header string) (*Meta, bool) {
 		m.Paging = fallback.Paging
 	}
 
+	if tmp.Progress != nil {
+		m.Progress = *tmp.Progress
+	} else {
+		m.Progress = fallback.Progress
+	}
+
+	if tmp.TabWidth != nil {
+		m.TabWidth = *tmp.TabWidth
+	} else {
+		m.TabWidth = fallback.TabWidth
+	}
+
+	if tmp.Background != nil {
+		m.Background = *tmp.Background
+	} else {
+		m.Background = fallback.Background
+	}
+
+	if tmp.Ascii != nil {
+		m.Ascii = *tmp.Ascii
+	} else {
+		m.Ascii = fallback.Ascii
+	}
+
+	if tmp.Templates != nil {
+		m.Templates = *tmp.Templates
+	} else {
+		m.Templates = fallback.Templates
+	}
+
+	if tmp.AllowExec != nil {
+		m.AllowExec = *tmp.AllowExec
+	} else {
+		m.AllowExec = fallback.AllowExec
+	}
+
+	if tmp.AutoPaginate != nil {
+		m.AutoPaginate = *tmp.AutoPaginate
+	} else {
+		m.AutoPaginate = fallback.AutoPaginate
+	}
+
+	if tmp.SearchPrompt != nil {
+		m.SearchPrompt = *tmp.SearchPrompt
+	} else {
+		m.SearchPrompt = fallback.SearchPrompt
+	}
+
+	if tmp.SearchPlaceholder != nil {
+		m.SearchPlaceholder = *tmp.SearchPlaceholder
+	} else {
+		m.SearchPlaceholder = fallback.SearchPlaceholder
+	}
+
+	if tmp.SearchColor != nil {
+		m.SearchColor = *tmp.SearchColor
+	} else {
+		m.SearchColor = fallback.SearchColor
+	}
+
+	if tmp.Watch != nil {
+		m.Watch = *tmp.Watch
+	} else {
+		m.Watch = fallback.Watch
+	}
+
+	if tmp.ExtendedSyntax != nil {
+		m.ExtendedSyntax = *tmp.ExtendedSyntax
+	} else {
+		m.ExtendedSyntax = fallback.ExtendedSyntax
+	}
+
+	if tmp.Tree != nil {
+		m.Tree = *tmp.Tree
+	} else {
+		m.Tree = fallback.Tree
+	}
+
+	if tmp.QuotePanels != nil {
+		m.QuotePanels = *tmp.QuotePanels
+	} else {
+		m.QuotePanels = fallback.QuotePanels
+	}
+
+	if tmp.IntroDelay != nil {
+		m.IntroDelay = *tmp.IntroDelay
+	} else {
+		m.IntroDelay = fallback.IntroDelay
+	}
+
+	if tmp.Contact != nil {
+		m.Contact = *tmp.Contact
+	} else {
+		m.Contact = fallback.Contact
+	}
+
+	if tmp.Email != nil {
+		m.Email = *tmp.Email
+	} else {
+		m.Email = fallback.Email
+	}
+
+	if tmp.Social != nil {
+		m.Social = *tmp.Social
+	} else {
+		m.Social = fallback.Social
+	}
+
+	if tmp.TypeTarget != nil {
+		m.TypeTarget = *tmp.TypeTarget
+	} else {
+		m.TypeTarget = fallback.TypeTarget
+	}
+
+	if tmp.TypeDelay != nil {
+		m.TypeDelay = *tmp.TypeDelay
+	} else {
+		m.TypeDelay = fallback.TypeDelay
+	}
+
+	if tmp.Canvas != nil {
+		m.Canvas = *tmp.Canvas
+	} else {
+		m.Canvas = fallback.Canvas
+	}
+
+	if tmp.Transition != nil {
+		m.Transition = *tmp.Transition
+	} else {
+		m.Transition = fallback.Transition
+	}
+
+	if tmp.TransitionScope != nil {
+		m.TransitionScope = *tmp.TransitionScope
+	} else {
+		m.TransitionScope = fallback.TransitionScope
+	}
+
+	if tmp.SlideFilter != nil {
+		m.SlideFilter = *tmp.SlideFilter
+	} else {
+		m.SlideFilter = fallback.SlideFilter
+	}
+
+	if tmp.Timer != nil {
+		m.Timer = *tmp.Timer
+	} else {
+		m.Timer = fallback.Timer
+	}
+
+	if tmp.Duration != nil {
+		m.Duration = *tmp.Duration
+	} else {
+		m.Duration = fallback.Duration
+	}
+
+	if tmp.CodeRunners != nil {
+		m.CodeRunners = *tmp.CodeRunners
+	} else {
+		m.CodeRunners = fallback.CodeRunners
+	}
+
+	if tmp.CodeTimeout != nil {
+		m.CodeTimeout = *tmp.CodeTimeout
+	} else {
+		m.CodeTimeout = fallback.CodeTimeout
+	}
+
+	if tmp.CodeLog != nil {
+		m.CodeLog = *tmp.CodeLog
+	} else {
+		m.CodeLog = fallback.CodeLog
+	}
+
+	if tmp.AutoAdvance != nil {
+		m.AutoAdvance = *tmp.AutoAdvance
+	} else {
+		m.AutoAdvance = fallback.AutoAdvance
+	}
+
+	if tmp.Vars != nil {
+		m.Vars = *tmp.Vars
+	} else {
+		m.Vars = fallback.Vars
+	}
+
+	if tmp.Header != nil {
+		m.Header = *tmp.Header
+	} else {
+		m.Header = fallback.Header
+	}
+
+	if tmp.Footer != nil {
+		m.Footer = *tmp.Footer
+	} else {
+		m.Footer = fallback.Footer
+	}
+
+	if tmp.HideChrome != nil {
+		m.HideChrome = *tmp.HideChrome
+	} else {
+		m.HideChrome = fallback.HideChrome
+	}
+
+	if tmp.Keys != nil {
+		m.Keys = *tmp.Keys
+	} else {
+		m.Keys = fallback.Keys
+	}
+
+	if tmp.CodeTheme != nil {
+		m.CodeTheme = *tmp.CodeTheme
+	} else {
+		m.CodeTheme = fallback.CodeTheme
+	}
+
+	if tmp.WordWrap != nil {
+		m.WordWrap = *tmp.WordWrap
+	} else {
+		m.WordWrap = fallback.WordWrap
+	}
+
+	if tmp.Align != nil {
+		m.Align = *tmp.Align
+	} else {
+		m.Align = fallback.Align
+	}
+
+	if tmp.CodeDir != nil {
+		m.CodeDir = *tmp.CodeDir
+	} else {
+		m.CodeDir = fallback.CodeDir
+	}
+
+	if tmp.CodeEnv != nil {
+		m.CodeEnv = *tmp.CodeEnv
+	} else {
+		m.CodeEnv = fallback.CodeEnv
+	}
+
+	if tmp.Separator != nil {
+		m.Separator = *tmp.Separator
+	} else {
+		m.Separator = fallback.Separator
+	}
+
+	if tmp.Padding != nil {
+		m.Padding = *tmp.Padding
+	} else {
+		m.Padding = fallback.Padding
+	}
+
+	if tmp.WatchInterval != nil {
+		m.WatchInterval = *tmp.WatchInterval
+	} else {
+		m.WatchInterval = fallback.WatchInterval
+	}
+
+	if tmp.OnSlide != nil {
+		m.OnSlide = *tmp.OnSlide
+	} else {
+		m.OnSlide = fallback.OnSlide
+	}
+
+	if tmp.Fit != nil {
+		m.Fit = *tmp.Fit
+	} else {
+		m.Fit = fallback.Fit
+	}
+
+	if tmp.ConfirmQuit != nil {
+		m.ConfirmQuit = *tmp.ConfirmQuit
+	} else {
+		m.ConfirmQuit = fallback.ConfirmQuit
+	}
+
+	if tmp.WordsPerMinute != nil {
+		m.WordsPerMinute = *tmp.WordsPerMinute
+	} else {
+		m.WordsPerMinute = fallback.WordsPerMinute
+	}
+
+	if tmp.SaveBookmarks != nil {
+		m.SaveBookmarks = *tmp.SaveBookmarks
+	} else {
+		m.SaveBookmarks = fallback.SaveBookmarks
+	}
+
+	if tmp.Diagrams != nil {
+		m.Diagrams = *tmp.Diagrams
+	} else {
+		m.Diagrams = fallback.Diagrams
+	}
+
 	return m, true
 }
 
+// Validate returns an error if the header isn't valid YAML or sets keys which
+// aren't metadata, which Parse ignores
+func Validate(header string) error {
+	var tmp parsedMeta
+	return yaml.UnmarshalStrict([]byte(header), &tmp)
+}
+
+// ThemeEnv is the environment variable naming the theme to use for slides
+// which don't set a theme in their metadata
+const ThemeEnv = "SLIDES_THEME"
+
 func defaultTheme() string {
-	return "default"
+	theme := os.Getenv(ThemeEnv)
+	if theme == "" {
+		return "default"
+	}
+	// Relative paths are relative to the working directory rather than the
+	// directory of the slides
+	if _, err := os.Stat(theme); err == nil {
+		if abs, err := filepath.Abs(theme); err == nil {
+			return abs
+		}
+	}
+	return theme
 }
 
 func defaultAuthor() string {
@@ -101,6 +531,10 @@ func defaultPaging() string {
 	return "Slide %d / %d"
 }
 
+func defaultWatch() bool {
+	return true
+}
+
 func parseDate(value string) string {
 	pairs := [][]string{
 		{"YYYY", "2006"},
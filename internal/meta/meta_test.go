@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os/user"
 	"testing"
+	"time"
 
 	"github.com/maaslalani/slides/internal/meta"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +27,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   date,
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -36,6 +38,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   date,
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -46,6 +49,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: "gopher",
 				Date:   date,
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -56,6 +60,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   date,
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -66,6 +71,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   "31/01/1970",
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -76,6 +82,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   "Jan 2, 2006",
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -86,6 +93,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   "2006-01-02",
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -96,6 +104,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   "2/1/06",
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -106,6 +115,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   "Jan 2, 2006",
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -116,6 +126,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   "January 02, 2006",
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -126,6 +137,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   date,
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -136,6 +148,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   date,
 				Paging: "%d of %d",
+				Watch:  true,
 			},
 		},
 		{
@@ -146,6 +159,450 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   date,
 				Paging: "Slide %d / %d",
+				Watch:  true,
+			},
+		},
+		{
+			name:      "Parse progress from header",
+			slideshow: fmt.Sprintf("---\nprogress: %q\n", "dots"),
+			want: &meta.Meta{
+				Theme:    "default",
+				Author:   user.Name,
+				Date:     date,
+				Paging:   "Slide %d / %d",
+				Watch:    true,
+				Progress: "dots",
+			},
+		},
+		{
+			name:      "Parse tab width from header",
+			slideshow: "---\ntabWidth: 4\n",
+			want: &meta.Meta{
+				Theme:    "default",
+				Author:   user.Name,
+				Date:     date,
+				Paging:   "Slide %d / %d",
+				Watch:    true,
+				TabWidth: 4,
+			},
+		},
+		{
+			name:      "Parse ascii from header",
+			slideshow: "---\nascii: true\n",
+			want: &meta.Meta{
+				Theme:  "default",
+				Author: user.Name,
+				Date:   date,
+				Paging: "Slide %d / %d",
+				Watch:  true,
+				Ascii:  true,
+			},
+		},
+		{
+			name:      "Parse templates and allowExec from header",
+			slideshow: "---\ntemplates: true\nallowExec: true\n",
+			want: &meta.Meta{
+				Theme:     "default",
+				Author:    user.Name,
+				Date:      date,
+				Paging:    "Slide %d / %d",
+				Watch:     true,
+				Templates: true,
+				AllowExec: true,
+			},
+		},
+		{
+			name:      "Parse search customization from header",
+			slideshow: "---\nsearchPrompt: \"find: \"\nsearchPlaceholder: term\nsearchColor: \"#ff0000\"\n",
+			want: &meta.Meta{
+				Theme:             "default",
+				Author:            user.Name,
+				Date:              date,
+				Paging:            "Slide %d / %d",
+				Watch:             true,
+				SearchPrompt:      "find: ",
+				SearchPlaceholder: "term",
+				SearchColor:       "#ff0000",
+			},
+		},
+		{
+			name:      "Parse watch from header",
+			slideshow: "---\nwatch: false\n",
+			want: &meta.Meta{
+				Theme:  "default",
+				Author: user.Name,
+				Date:   date,
+				Paging: "Slide %d / %d",
+				Watch:  false,
+			},
+		},
+		{
+			name:      "Parse extended syntax from header",
+			slideshow: "---\nextendedSyntax: true\n",
+			want: &meta.Meta{
+				Theme:          "default",
+				Author:         user.Name,
+				Date:           date,
+				Paging:         "Slide %d / %d",
+				Watch:          true,
+				ExtendedSyntax: true,
+			},
+		},
+		{
+			name:      "Parse tree from header",
+			slideshow: "---\ntree: true\n",
+			want: &meta.Meta{
+				Theme:  "default",
+				Author: user.Name,
+				Date:   date,
+				Paging: "Slide %d / %d",
+				Watch:  true,
+				Tree:   true,
+			},
+		},
+		{
+			name:      "Parse quote panels from header",
+			slideshow: "---\nquotePanels: true\n",
+			want: &meta.Meta{
+				Theme:       "default",
+				Author:      user.Name,
+				Date:        date,
+				Paging:      "Slide %d / %d",
+				Watch:       true,
+				QuotePanels: true,
+			},
+		},
+		{
+			name:      "Parse intro delay from header",
+			slideshow: "---\nintroDelay: 2s\n",
+			want: &meta.Meta{
+				Theme:      "default",
+				Author:     user.Name,
+				Date:       date,
+				Paging:     "Slide %d / %d",
+				Watch:      true,
+				IntroDelay: 2 * time.Second,
+			},
+		},
+		{
+			name:      "Parse contact information from header",
+			slideshow: "---\ncontact: gopher.dev\nemail: gopher@example.com\nsocial: \"@gopher\"\n",
+			want: &meta.Meta{
+				Theme:   "default",
+				Author:  user.Name,
+				Date:    date,
+				Paging:  "Slide %d / %d",
+				Watch:   true,
+				Contact: "gopher.dev",
+				Email:   "gopher@example.com",
+				Social:  "@gopher",
+			},
+		},
+		{
+			name:      "Parse typing from header",
+			slideshow: "---\ntypeTarget: demo.1\ntypeDelay: 20ms\n",
+			want: &meta.Meta{
+				Theme:      "default",
+				Author:     user.Name,
+				Date:       date,
+				Paging:     "Slide %d / %d",
+				Watch:      true,
+				TypeTarget: "demo.1",
+				TypeDelay:  20 * time.Millisecond,
+			},
+		},
+		{
+			name:      "Parse canvas from header",
+			slideshow: "---\ncanvas: 80x24\n",
+			want: &meta.Meta{
+				Theme:  "default",
+				Author: user.Name,
+				Date:   date,
+				Paging: "Slide %d / %d",
+				Watch:  true,
+				Canvas: "80x24",
+			},
+		},
+		{
+			name:      "Parse transition from header",
+			slideshow: "---\ntransition: wipe\ntransitionScope: sections\n",
+			want: &meta.Meta{
+				Theme:           "default",
+				Author:          user.Name,
+				Date:            date,
+				Paging:          "Slide %d / %d",
+				Watch:           true,
+				Transition:      "wipe",
+				TransitionScope: "sections",
+			},
+		},
+		{
+			name:      "Parse slide filter from header",
+			slideshow: "---\nslideFilter: my-preprocessor --flag\n",
+			want: &meta.Meta{
+				Theme:       "default",
+				Author:      user.Name,
+				Date:        date,
+				Paging:      "Slide %d / %d",
+				Watch:       true,
+				SlideFilter: "my-preprocessor --flag",
+			},
+		},
+		{
+			name:      "Parse timer from header",
+			slideshow: "---\ntimer: true\nduration: 20m\n",
+			want: &meta.Meta{
+				Theme:    "default",
+				Author:   user.Name,
+				Date:     date,
+				Paging:   "Slide %d / %d",
+				Watch:    true,
+				Timer:    true,
+				Duration: 20 * time.Minute,
+			},
+		},
+		{
+			name:      "Parse code runners from header",
+			slideshow: "---\ncodeRunners:\n  ts: deno run %s\ncodeTimeout: 10s\n",
+			want: &meta.Meta{
+				Theme:       "default",
+				Author:      user.Name,
+				Date:        date,
+				Paging:      "Slide %d / %d",
+				Watch:       true,
+				CodeRunners: map[string]string{"ts": "deno run %s"},
+				CodeTimeout: 10 * time.Second,
+			},
+		},
+		{
+			name:      "Parse code log from header",
+			slideshow: "---\ncodeLog: run.log\n",
+			want: &meta.Meta{
+				Theme:   "default",
+				Author:  user.Name,
+				Date:    date,
+				Paging:  "Slide %d / %d",
+				Watch:   true,
+				CodeLog: "run.log",
+			},
+		},
+		{
+			name:      "Parse auto advance from header",
+			slideshow: "---\nautoAdvance: 10s\n",
+			want: &meta.Meta{
+				Theme:       "default",
+				Author:      user.Name,
+				Date:        date,
+				Paging:      "Slide %d / %d",
+				Watch:       true,
+				AutoAdvance: 10 * time.Second,
+			},
+		},
+		{
+			name:      "Parse variables from header",
+			slideshow: "---\nvars:\n  company: Acme\n",
+			want: &meta.Meta{
+				Theme:  "default",
+				Author: user.Name,
+				Date:   date,
+				Paging: "Slide %d / %d",
+				Watch:  true,
+				Vars:   map[string]string{"company": "Acme"},
+			},
+		},
+		{
+			name:      "Parse header and footer from header",
+			slideshow: "---\nheader: My Talk\nfooter: \"@me\"\n",
+			want: &meta.Meta{
+				Theme:  "default",
+				Author: user.Name,
+				Date:   date,
+				Paging: "Slide %d / %d",
+				Watch:  true,
+				Header: "My Talk",
+				Footer: "@me",
+			},
+		},
+		{
+			name:      "Parse hide chrome from header",
+			slideshow: "---\nhideChrome: true\n",
+			want: &meta.Meta{
+				Theme:      "default",
+				Author:     user.Name,
+				Date:       date,
+				Paging:     "Slide %d / %d",
+				Watch:      true,
+				HideChrome: true,
+			},
+		},
+		{
+			name:      "Parse keys from header",
+			slideshow: "---\nkeys:\n  next: [x, right]\n",
+			want: &meta.Meta{
+				Theme:  "default",
+				Author: user.Name,
+				Date:   date,
+				Paging: "Slide %d / %d",
+				Watch:  true,
+				Keys:   map[string][]string{"next": {"x", "right"}},
+			},
+		},
+		{
+			name:      "code theme",
+			slideshow: "---\ncodeTheme: monokai\n",
+			want: &meta.Meta{
+				Theme:     "default",
+				Author:    user.Name,
+				Date:      date,
+				Paging:    "Slide %d / %d",
+				Watch:     true,
+				CodeTheme: "monokai",
+			},
+		},
+		{
+			name:      "word wrap and alignment",
+			slideshow: "---\nwordWrap: 80\nalign: center\n",
+			want: &meta.Meta{
+				Theme:    "default",
+				Author:   user.Name,
+				Date:     date,
+				Paging:   "Slide %d / %d",
+				Watch:    true,
+				WordWrap: 80,
+				Align:    "center",
+			},
+		},
+		{
+			name:      "code directory and environment",
+			slideshow: "---\ncodeDir: ./demo\ncodeEnv:\n  FOO: bar\n",
+			want: &meta.Meta{
+				Theme:   "default",
+				Author:  user.Name,
+				Date:    date,
+				Paging:  "Slide %d / %d",
+				Watch:   true,
+				CodeDir: "./demo",
+				CodeEnv: map[string]string{"FOO": "bar"},
+			},
+		},
+		{
+			name:      "separator",
+			slideshow: "---\nseparator: \"===\"\n",
+			want: &meta.Meta{
+				Theme:     "default",
+				Author:    user.Name,
+				Date:      date,
+				Paging:    "Slide %d / %d",
+				Watch:     true,
+				Separator: "===",
+			},
+		},
+		{
+			name:      "padding",
+			slideshow: "---\npadding: 2 4\n",
+			want: &meta.Meta{
+				Theme:   "default",
+				Author:  user.Name,
+				Date:    date,
+				Paging:  "Slide %d / %d",
+				Watch:   true,
+				Padding: "2 4",
+			},
+		},
+		{
+			name:      "single padding",
+			slideshow: "---\npadding: 2\n",
+			want: &meta.Meta{
+				Theme:   "default",
+				Author:  user.Name,
+				Date:    date,
+				Paging:  "Slide %d / %d",
+				Watch:   true,
+				Padding: "2",
+			},
+		},
+		{
+			name:      "watch interval",
+			slideshow: "---\nwatchInterval: 5s\n",
+			want: &meta.Meta{
+				Theme:         "default",
+				Author:        user.Name,
+				Date:          date,
+				Paging:        "Slide %d / %d",
+				Watch:         true,
+				WatchInterval: 5 * time.Second,
+			},
+		},
+		{
+			name:      "on slide",
+			slideshow: "---\nonSlide: ./hook.sh {page}\n",
+			want: &meta.Meta{
+				Theme:   "default",
+				Author:  user.Name,
+				Date:    date,
+				Paging:  "Slide %d / %d",
+				Watch:   true,
+				OnSlide: "./hook.sh {page}",
+			},
+		},
+		{
+			name:      "fit",
+			slideshow: "---\nfit: true\n",
+			want: &meta.Meta{
+				Theme:  "default",
+				Author: user.Name,
+				Date:   date,
+				Paging: "Slide %d / %d",
+				Watch:  true,
+				Fit:    true,
+			},
+		},
+		{
+			name:      "confirm quit",
+			slideshow: "---\nconfirmQuit: true\n",
+			want: &meta.Meta{
+				Theme:       "default",
+				Author:      user.Name,
+				Date:        date,
+				Paging:      "Slide %d / %d",
+				Watch:       true,
+				ConfirmQuit: true,
+			},
+		},
+		{
+			name:      "words per minute",
+			slideshow: "---\nwordsPerMinute: 150\n",
+			want: &meta.Meta{
+				Theme:          "default",
+				Author:         user.Name,
+				Date:           date,
+				Paging:         "Slide %d / %d",
+				Watch:          true,
+				WordsPerMinute: 150,
+			},
+		},
+		{
+			name:      "save bookmarks",
+			slideshow: "---\nsaveBookmarks: true\n",
+			want: &meta.Meta{
+				Theme:         "default",
+				Author:        user.Name,
+				Date:          date,
+				Paging:        "Slide %d / %d",
+				Watch:         true,
+				SaveBookmarks: true,
+			},
+		},
+		{
+			name:      "diagrams",
+			slideshow: "---\ndiagrams: true\n",
+			want: &meta.Meta{
+				Theme:    "default",
+				Author:   user.Name,
+				Date:     date,
+				Paging:   "Slide %d / %d",
+				Watch:    true,
+				Diagrams: true,
 			},
 		},
 		{
@@ -156,6 +613,7 @@ func TestMeta_ParseHeader(t *testing.T) {
 				Author: user.Name,
 				Date:   date,
 				Paging: "Slide %d / %d",
+				Watch:  true,
 			},
 		},
 	}
@@ -172,6 +630,37 @@ func TestMeta_ParseHeader(t *testing.T) {
 	}
 }
 
+func TestParse_themeEnv(t *testing.T) {
+	t.Setenv(meta.ThemeEnv, "dark")
+
+	m, _ := meta.New().Parse("author: gopher")
+	assert.Equal(t, "dark", m.Theme)
+
+	m, _ = meta.New().Parse("theme: light")
+	assert.Equal(t, "light", m.Theme)
+}
+
+func TestParse_frontMatter(t *testing.T) {
+	// Front matter generated by other tools, which end YAML documents with
+	// ... and may list several authors
+	m, exists := meta.New().Parse("---\ntitle: Talk\nauthor: [A, B]\ntheme: dark\npaging: \"%d\"\n...\n")
+	assert.True(t, exists)
+	assert.Equal(t, "dark", m.Theme)
+	assert.Equal(t, "%d", m.Paging)
+
+	_, exists = meta.New().Parse("# Heading")
+	assert.False(t, exists)
+
+	_, exists = meta.New().Parse("Just some text")
+	assert.False(t, exists)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, meta.Validate("theme: dark\nauthor: gopher"))
+	assert.Error(t, meta.Validate("theem: dark"))
+	assert.Error(t, meta.Validate("paging: [broken"))
+}
+
 func TestNew(t *testing.T) {
 	tests := []struct {
 		name string
@@ -6,14 +6,48 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/maaslalani/slides/internal/annotate"
+	"github.com/maaslalani/slides/internal/announce"
+	"github.com/maaslalani/slides/internal/asciidoc"
+	"github.com/maaslalani/slides/internal/badge"
+	"github.com/maaslalani/slides/internal/bookmark"
+	"github.com/maaslalani/slides/internal/capability"
+	"github.com/maaslalani/slides/internal/columns"
+	"github.com/maaslalani/slides/internal/contact"
+	"github.com/maaslalani/slides/internal/diagram"
+	"github.com/maaslalani/slides/internal/directive"
+	"github.com/maaslalani/slides/internal/emphasis"
+	"github.com/maaslalani/slides/internal/extended"
 	"github.com/maaslalani/slides/internal/file"
+	"github.com/maaslalani/slides/internal/fragment"
+	"github.com/maaslalani/slides/internal/graphics"
+	"github.com/maaslalani/slides/internal/images"
 	"github.com/maaslalani/slides/internal/navigation"
+	"github.com/maaslalani/slides/internal/outline"
+	"github.com/maaslalani/slides/internal/overview"
+	"github.com/maaslalani/slides/internal/pace"
 	"github.com/maaslalani/slides/internal/process"
+	"github.com/maaslalani/slides/internal/progress"
+	"github.com/maaslalani/slides/internal/quote"
+	"github.com/maaslalani/slides/internal/rehearsal"
+	"github.com/maaslalani/slides/internal/timer"
+	"github.com/maaslalani/slides/internal/tmpl"
+	"github.com/maaslalani/slides/internal/transition"
+	"github.com/maaslalani/slides/internal/typist"
 
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
@@ -25,6 +59,8 @@ import (
 
 const (
 	delimiter = "\n---\n"
+	// documentEnd ends YAML front matter as an alternative to the delimiter
+	documentEnd = "\n...\n"
 )
 
 var (
@@ -39,50 +75,676 @@ var (
 		b.Left = "┤"
 		return titleStyle.Copy().BorderStyle(b)
 	}()
+
+	// asciiBorder is used in terminals that can't display box-drawing
+	// characters
+	asciiBorder = lipgloss.Border{
+		Top:         "-",
+		Bottom:      "-",
+		Left:        "|",
+		Right:       "|",
+		TopLeft:     "+",
+		TopRight:    "+",
+		BottomLeft:  "+",
+		BottomRight: "+",
+	}
+
+	// plainTitleStyle and plainInfoStyle have no borders, for plain text
+	plainTitleStyle = lipgloss.NewStyle().Padding(0, 1)
+	plainInfoStyle  = plainTitleStyle.Copy()
+
+	// reStyle matches the escape sequences setting colors and text styles,
+	// which are removed from plain text
+	reStyle = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+	asciiTitleStyle = func() lipgloss.Style {
+		b := asciiBorder
+		b.Right = "+"
+		return titleStyle.Copy().BorderStyle(b)
+	}()
+
+	asciiInfoStyle = func() lipgloss.Style {
+		b := asciiBorder
+		b.Left = "+"
+		return titleStyle.Copy().BorderStyle(b)
+	}()
 )
 
 type Model struct {
-	Slides   []string
-	Page     int
-	Author   string
-	Date     string
-	Theme    glamour.TermRendererOption
-	Paging   string
+	Slides []string
+	Page   int
+	Author string
+	Date   string
+	// dateLayout is the layout of the date, which {{date}} tokens in the
+	// slides are formatted with
+	dateLayout string
+	Theme      glamour.TermRendererOption
+	// ThemeName is the name of the theme currently in use
+	ThemeName string
+	// ThemesDir is the directory of custom themes which can be named like
+	// the built-in themes
+	ThemesDir string
+	Paging    string
+	Progress  string
+	// Background is the path to an image drawn behind every slide which
+	// doesn't set its own background
+	Background string
+	// Separator separates the slides instead of the separator of the
+	// metadata, or --- if neither sets one
+	Separator string
+	// Ascii replaces the box-drawing characters of the header and
+	// footer with ASCII characters
+	Ascii bool
+	// Plain renders the slides and the header, footer and status bar as
+	// plain text, without colors, borders or any other escape sequences
+	Plain bool
+	// Templates evaluates slides as Go templates before rendering them
+	Templates bool
+	// AllowExec allows slides to run commands
+	AllowExec bool
+	// Exec allows slides to run commands even if the metadata doesn't set
+	// allowExec, set by --exec
+	Exec bool
+	// ExtendedSyntax renders highlights, subscripts and superscripts
+	ExtendedSyntax bool
+	// Diagrams draws the flowcharts of mermaid and dot code blocks instead
+	// of showing their source
+	Diagrams bool
+	// AutoPaginate splits slides which are too tall for the viewport into
+	// multiple pages
+	AutoPaginate bool
+	// Fit tightens the blank lines of slides which are too tall for the
+	// viewport so that they fit
+	Fit bool
+	// ConfirmQuit asks to press the quit key again before quitting, ctrl+c
+	// always quits right away
+	ConfirmQuit bool
+	// SaveBookmarks stores the bookmarks in a file next to the slides, so
+	// that they are kept the next time the slides are presented
+	SaveBookmarks bool
+	// bookmarks are the slides bookmarked to jump between
+	bookmarks bookmark.Set
+	// IntroDelay is the time after a slide is shown during which navigation
+	// is ignored, slides can set their own with an intro directive
+	IntroDelay time.Duration
+	// TypeTarget is the tmux pane code blocks are typed into
+	TypeTarget string
+	// TypeDelay is the time between each key typed into the TypeTarget
+	TypeDelay time.Duration
+	// Canvas is the fixed size, such as 80x24, of the area the slides are
+	// presented in, which is centered in the terminal
+	Canvas string
+	// Transition animates changing slides, TransitionScope is which changes
+	// are animated
+	Transition      string
+	TransitionScope string
+	// SlideFilter is a command every slide is piped through before it is
+	// rendered, which requires AllowExec
+	SlideFilter string
+	// OnSlide is a command run whenever the slide changes, which requires
+	// AllowExec. {page}, {total} and {title} are replaced with the slide.
+	OnSlide string
+	// hookErrors receives the errors of the OnSlide command
+	hookErrors chan error
+	// Timer displays the time elapsed since the presentation started in the
+	// footer, which turns red once it runs past the Duration of the talk
+	Timer    bool
+	Duration time.Duration
+	// CodeRunners maps languages to the commands running their code blocks,
+	// in addition to the built-in languages. CodeTimeout stops code which
+	// runs for longer.
+	CodeRunners map[string]string
+	CodeTimeout time.Duration
+	// AutoAdvance is how long each slide is shown before advancing to the
+	// next one, slides don't advance on their own if it is zero
+	AutoAdvance time.Duration
+	// Vars are the variables given on the command line, which take
+	// precedence over the variables in the metadata
+	Vars map[string]string
+	// Header and Footer are displayed at the left of the header and footer,
+	// the header shows "Mr. Pager" if it is empty
+	Header string
+	Footer string
+	// HideChrome starts the presentation without the header, footer and
+	// status bar, which are toggled while presenting
+	HideChrome bool
+	// WordWrap is the width slides are wrapped at when the viewport is
+	// wider, slides are wrapped at the width of the viewport if it is zero
+	WordWrap int
+	// Align places the slide at the left, center or right of the viewport
+	Align string
+	// Padding is the vertical and horizontal padding around the slide, such
+	// as "1 2", the default padding is used if it is empty
+	Padding string
+	// CodeTheme is the chroma style code blocks are highlighted with, the
+	// style of the theme is used if it is empty
+	CodeTheme string
+	// CodeDir is the working directory code blocks are executed in, and
+	// CodeEnv the environment variables they are executed with in addition
+	// to the environment of slides
+	CodeDir string
+	CodeEnv map[string]string
+	// CodeLog is the file the code and output of every execution is
+	// appended to, nothing is logged if it is empty
+	CodeLog string
+	// QuotePanels renders blockquotes as styled panels with their attribution
+	QuotePanels bool
+	// Tree navigates the slides in two dimensions, horizontally between the
+	// sections and vertically through the slides of each section
+	Tree     bool
 	FileName string
+	// NoWatch disables reloading the slides when their source changes
+	NoWatch bool
+	// Presenter prints the speaker notes of each slide to stderr when it is
+	// shown, so they can be followed in a second terminal
+	Presenter bool
+	// NoContact disables appending a closing slide with the contact
+	// information from the metadata
+	NoContact bool
+	// Clipboard presents the contents of the system clipboard instead of a
+	// file, reloading whenever the clipboard changes
+	Clipboard bool
+	// Stream presents the slides piped to stdin as they arrive, instead of
+	// waiting for all of them
+	Stream   bool
 	viewport viewport.Model
 	buffer   string
 	// VirtualText is used for additional information that is not part of the
 	// original slides, it will be displayed on a slide and reset on page change
 	VirtualText string
-	Search      navigation.Search
-	ready       bool
+	// output is the output of the code executed on the current slide, which
+	// is revealed one line at a time, up to revealed
+	output   []string
+	revealed int
+	// subPage is the page of the current slide which is displayed when the
+	// slide is auto paginated
+	subPage int
+	// fragment is the last fragment of the current slide which is revealed
+	fragment int
+	// focus is the one based index of the code block of the current slide
+	// which is focused, hiding the rest of the slide. It is zero when no
+	// block is focused.
+	focus int
+	// selectedBlock is the one based index of the code block of the current
+	// slide which is executed and copied, instead of every block. It is zero
+	// when no block is selected.
+	selectedBlock int
+	// transitionFrom is the rendered slide being transitioned away from and
+	// transitionFrame the frame of the transition, which is zero when no
+	// transition is in progress. Every transition has a new transitionID so
+	// the frames of an interrupted transition are ignored.
+	transitionFrom  string
+	transitionFrame int
+	transitionID    int
+	// paused is whether auto-advancing was paused by a key press. Every
+	// time auto-advancing starts it has a new advanceID so that the ticks
+	// of earlier runs are ignored.
+	paused    bool
+	advanceID int
+	// timerID is the id of the ticks of the timer, the timer is started
+	// again with a new id when the agenda is shown so that the ticks of an
+	// earlier timer are ignored
+	timerID int
+	// toast is a short message shown in the status bar, which is dismissed
+	// after toastDuration. Every toast has a new toastID so that a toast
+	// isn't dismissed by the tick of an earlier one.
+	toast   string
+	toastID int
+	// reloaded is when the slides were last reloaded after their source
+	// changed, it is zero until they are
+	reloaded time.Time
+	// themeModTime is when the file of the theme in use was last modified,
+	// the theme is reloaded when it changes
+	themeModTime time.Time
+	// annotation is the prompt for a note to add to the current slide's
+	// source, which is displayed while annotating
+	annotation textinput.Model
+	annotating bool
+	// about is whether the information panel about the deck is displayed
+	about bool
+	// overview is whether the grid of all slides is displayed in place of
+	// the current slide, with selected as the slide under the cursor
+	overview bool
+	selected int
+	// notes are the speaker notes of each slide, showNotes is whether the
+	// notes of the current slide are displayed below it in notesViewport
+	notes         [][]string
+	showNotes     bool
+	notesViewport viewport.Model
+	// hasHeader is whether the source starts with a metadata header which is
+	// not presented as a slide
+	hasHeader bool
+	// runs is the status of the last execution of each slide's code blocks
+	runs map[int]runStatus
+	// clipboard is the clipboard content the slides were last loaded from
+	clipboard string
+	// stream receives the content piped to stdin so far while streaming,
+	// and streamed is the content the slides were last loaded from
+	stream   <-chan string
+	streamed string
+	// keys are the key bindings of each slide which jump to other slides
+	keys []navigation.KeyMap
+	// sections group the slides by their top-level headings
+	sections []outline.Section
+	// vertical is the tree of the sections separated by the separator when
+	// the slides are split into vertical slides, it has no sections otherwise
+	vertical navigation.Tree
+	// agenda is whether the agenda sidebar is visible
+	agenda bool
+	// width is the width of the terminal
+	width int
+	// screenWidth and screenHeight are the size of the terminal, which is
+	// larger than the presentation when it is presented in a canvas
+	screenWidth  int
+	screenHeight int
+	// start is when the presentation started
+	start time.Time
+	// baseDir is the directory relative paths in the slides are resolved
+	// against
+	baseDir string
+	// baseURL is the URL the images and themes of slides fetched from a URL
+	// are resolved against, it is nil for other slides
+	baseURL *url.URL
+	// watch is whether the metadata allows reloading the slides when their
+	// source changes
+	watch bool
+	// WatchInterval is how often the slides are checked for changes, every
+	// second if it is zero
+	WatchInterval time.Duration
+	// WordsPerMinute is the speaking rate the time taken to present the
+	// slides is estimated with
+	WordsPerMinute int
+	// words is the number of spoken words of each slide
+	words []int
+	// bindings translates the keys bound to actions in the metadata
+	bindings navigation.Bindings
+	// chromeHidden is whether the header, footer and status bar are hidden so
+	// that the slide fills the terminal
+	chromeHidden bool
+	// locked prevents navigating away from the current slide
+	locked bool
+	// interactive is when navigation is accepted again after the intro
+	// delay of the current slide
+	interactive time.Time
+	// quitting is when pressing the quit key again no longer quits, after
+	// it is pressed once with ConfirmQuit
+	quitting time.Time
+	Search   navigation.Search
+	// Finder picks a slide to go to by its title
+	Finder navigation.Finder
+	// Capabilities are the features supported by the terminal, used to pick
+	// which branch of conditional content is presented
+	Capabilities capability.Set
+	// Rehearsal records the time spent on each slide, it is nil unless the
+	// presentation is being rehearsed
+	Rehearsal *rehearsal.Recorder
+	// Announcer publishes the current slide whenever it changes, it is nil
+	// unless the slides are announced
+	Announcer *announce.Announcer
+	// QuitCmd is run when q quits the presentation, it is tea.Quit unless
+	// the presentation returns somewhere else, such as to a menu of decks.
+	// ctrl+c always quits.
+	QuitCmd tea.Cmd
+	// Output is the writer the presentation is drawn on, images are drawn
+	// on it too. It is os.Stdout unless set.
+	Output io.Writer
+	// Console shows the presenter console, with the elapsed time, a preview
+	// of the next slide and the speaker notes beside the current slide
+	Console bool
+	// Follow receives the slides announced by a presenter console, which
+	// the current slide follows. It is nil unless following a console.
+	Follow   <-chan announce.Slide
+	ready    bool
+	content  string
+	graphics graphics.Protocol
+	// customTheme is the theme set in the metadata, which is included when
+	// cycling through the themes if it isn't a built-in theme
+	customTheme string
+	// backgrounds caches the escape sequences drawing background images, and
+	// the images of the slides
+	backgrounds map[string]string
+	// separator separates the slides, the default delimiter unless another
+	// separator is given on the command line or in the metadata
+	separator string
+	// placements are where the images of each rendered slide are drawn,
+	// keyed like the rendered slides
+	placements map[renderKey][]images.Placement
+	// filtered caches the output of the SlideFilter for each slide
+	filtered map[string]string
+	// rendered caches the rendered slides, it is cleared when the metadata
+	// changes or the width of the viewport changes
+	rendered map[renderKey]string
+	// header is the metadata header of the slides as they were last loaded
+	header string
+	// prerendering is whether the slides are being rendered up front in the
+	// background, which happens once the size of the terminal is known.
+	// prerendered is the number of slides rendered so far.
+	prerendering bool
+	prerendered  int
+	// prerenders are the slides rendered in the background
+	prerenders <-chan prerenderedMsg
+	// liveSlides are the live slides as they were last rendered. They are
+	// rendered again in the background as their page is entered and as the
+	// time changes, rather than whenever they are viewed.
+	liveSlides map[liveKey]liveSlide
+	// refreshing is whether live slides are being rendered in the
+	// background, entered and ticked whether the page and the time changed
+	// since they were last rendered
+	refreshing, entered, ticked bool
+}
+
+// renderKey identifies a rendered slide, it includes everything besides the
+// page and the width which changes how the slide is rendered
+type renderKey struct {
+	page        int
+	width       int
+	fragment    int
 	content     string
+	theme       string
+	virtualText string
+}
+
+// live returns the key of the live slide the key renders, live slides are
+// replaced as they are rendered again rather than kept for each render
+func (k renderKey) live() liveKey {
+	return liveKey{page: k.page, width: k.width, content: k.content}
+}
+
+// liveKey identifies a live slide
+type liveKey struct {
+	page    int
+	width   int
+	content string
+}
+
+// liveSlide is a live slide as it was last rendered, along with where its
+// images are drawn and the key it was rendered with
+type liveSlide struct {
+	key        renderKey
+	slide      string
+	placements []images.Placement
+}
+
+// liveView is a live slide in view, along with the model rendering it as it
+// is displayed
+type liveView struct {
+	m       Model
+	content string
 }
 
 type fileWatchMsg struct{}
 
-var fileInfo os.FileInfo
+// prerenderedMsg is a slide rendered in the background, along with where
+// its images are drawn
+type prerenderedMsg struct {
+	key        renderKey
+	slide      string
+	placements []images.Placement
+}
+
+// refreshedMsg is the live slides in view rendered in the background
+type refreshedMsg []liveSlide
+
+// graphicsMsg is the escape sequences drawing the images of the slide
+type graphicsMsg struct {
+	seq string
+}
+
+// prerenderDoneMsg is sent once every slide has been rendered in the
+// background
+type prerenderDoneMsg struct{}
+
+func prerenderCmd(prerenders <-chan prerenderedMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-prerenders
+		if !ok {
+			return prerenderDoneMsg{}
+		}
+		return msg
+	}
+}
+
+type clipboardWatchMsg struct{}
+
+// timerMsg redraws the elapsed time and the {{time}} tokens every second
+type timerMsg struct {
+	id int
+}
+
+// followMsg is the slide announced by the console being followed
+type followMsg announce.Slide
+
+func followCmd(slides <-chan announce.Slide) tea.Cmd {
+	return func() tea.Msg {
+		return followMsg(<-slides)
+	}
+}
+
+// streamMsg is sent when more of the slides are piped to stdin while
+// streaming, with all of the content read so far. It is done once stdin is
+// read to the end.
+type streamMsg struct {
+	content string
+	done    bool
+}
+
+func streamCmd(stream <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		content, ok := <-stream
+		return streamMsg{content: content, done: !ok}
+	}
+}
+
+// hookErrorMsg is sent when the OnSlide command fails
+type hookErrorMsg struct {
+	err error
+}
+
+func hookErrorCmd(errs <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		return hookErrorMsg{err: <-errs}
+	}
+}
+
+// transitionMsg advances the frame of a transition
+type transitionMsg struct {
+	id int
+}
+
+// advanceMsg advances to the next slide when auto-advancing
+type advanceMsg struct {
+	id int
+}
+
+func advanceCmd(id int, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return advanceMsg{id: id}
+	})
+}
+
+// toastDuration is how long a toast is shown for
+const toastDuration = 2 * time.Second
+
+// toastMsg dismisses the toast when it is shown for long enough
+type toastMsg struct {
+	id int
+}
+
+// notify shows the message as a toast, returning the command which dismisses
+// it again
+func (m *Model) notify(message string) tea.Cmd {
+	if message == "" {
+		return nil
+	}
+	m.toast = message
+	m.toastID++
+	id := m.toastID
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastMsg{id: id}
+	})
+}
+
+// quit quits the presentation, or with ConfirmQuit asks to press the quit
+// key again within toastDuration to quit
+func (m *Model) quit(key string) tea.Cmd {
+	if !m.ConfirmQuit || time.Now().Before(m.quitting) {
+		return m.quitCmd()
+	}
+	m.quitting = time.Now().Add(toastDuration)
+	return m.notify("Press " + key + " again to quit")
+}
+
+// writer returns the writer the presentation is drawn on
+func (m Model) writer() io.Writer {
+	if m.Output != nil {
+		return m.Output
+	}
+	return os.Stdout
+}
+
+// quitCmd returns the command quitting the presentation
+func (m Model) quitCmd() tea.Cmd {
+	if m.QuitCmd != nil {
+		return m.QuitCmd
+	}
+	return tea.Quit
+}
+
+// typedMsg is sent once code has been typed into the TypeTarget
+type typedMsg struct {
+	err error
+}
+
+// fileSettledMsg is sent a moment after the slides changed, to reload them
+// once they have stopped changing
+type fileSettledMsg struct {
+	modTime time.Time
+}
+
+// modTime is when the slides were last modified
+var modTime time.Time
+
+// themeSettledMsg is sent a moment after the file of the theme changed, to
+// reload the theme once it has stopped changing
+type themeSettledMsg struct {
+	modTime time.Time
+}
+
+const (
+	// defaultWatchInterval is how often the slides are checked for changes
+	// unless the metadata sets the interval
+	defaultWatchInterval = time.Second
+	// watchDebounce is how long the slides have to stay unchanged before
+	// they are reloaded, so that slides which are still being written
+	// aren't reloaded
+	watchDebounce = 250 * time.Millisecond
+)
 
 func (m Model) Init() tea.Cmd {
-	if m.FileName == "" {
+	var cmds []tea.Cmd
+	if m.ticks() {
+		cmds = append(cmds, timerCmd(m.timerID))
+	}
+	if m.Follow != nil {
+		cmds = append(cmds, followCmd(m.Follow))
+	}
+	if m.hookErrors != nil {
+		cmds = append(cmds, hookErrorCmd(m.hookErrors))
+	}
+	if m.stream != nil {
+		cmds = append(cmds, streamCmd(m.stream))
+	}
+	if m.AutoAdvance > 0 {
+		cmds = append(cmds, advanceCmd(m.advanceID, m.AutoAdvance))
+	}
+	if cmd := m.watchCmd(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// watchCmd returns the command reloading the slides when their source
+// changes, if they are watched
+func (m Model) watchCmd() tea.Cmd {
+	if m.NoWatch || !m.watch {
+		return nil
+	}
+	if m.Clipboard {
+		return clipboardWatchCmd()
+	}
+	// Slides fetched from a URL aren't reloaded
+	if m.FileName == "" || file.IsURL(m.FileName) {
 		return nil
 	}
-	fileInfo, _ = os.Stat(m.FileName)
-	return fileWatchCmd()
+	modTime, _ = file.ModTime(m.FileName)
+	return fileWatchCmd(m.watchInterval())
 }
 
-func fileWatchCmd() tea.Cmd {
-	return tea.Every(time.Second, func(t time.Time) tea.Msg {
+// watchInterval returns how often the slides are checked for changes
+func (m Model) watchInterval() time.Duration {
+	if m.WatchInterval > 0 {
+		return m.WatchInterval
+	}
+	return defaultWatchInterval
+}
+
+func fileWatchCmd(interval time.Duration) tea.Cmd {
+	return tea.Every(interval, func(t time.Time) tea.Msg {
 		return fileWatchMsg{}
 	})
 }
 
+func fileSettledCmd(modTime time.Time) tea.Cmd {
+	return tea.Tick(watchDebounce, func(t time.Time) tea.Msg {
+		return fileSettledMsg{modTime: modTime}
+	})
+}
+
+func themeSettledCmd(modTime time.Time) tea.Cmd {
+	return tea.Tick(watchDebounce, func(t time.Time) tea.Msg {
+		return themeSettledMsg{modTime: modTime}
+	})
+}
+
+// ticks returns whether anything presented changes by the second: the
+// timer, the console, the agenda or slides showing the time
+func (m Model) ticks() bool {
+	if m.Timer || m.Console || m.agenda {
+		return true
+	}
+	for _, slide := range m.Slides {
+		if tmpl.HasDates(slide) {
+			return true
+		}
+	}
+	return false
+}
+
+func timerCmd(id int) tea.Cmd {
+	return tea.Every(time.Second, func(t time.Time) tea.Msg {
+		return timerMsg{id: id}
+	})
+}
+
+func clipboardWatchCmd() tea.Cmd {
+	return tea.Every(time.Second, func(t time.Time) tea.Msg {
+		return clipboardWatchMsg{}
+	})
+}
+
 func (m *Model) Load() error {
 	var content string
 	var err error
 
-	if m.FileName != "" {
-		content, err = readFile(m.FileName)
+	if m.Clipboard {
+		content, err = readClipboard()
+		m.clipboard = content
+	} else if file.IsURL(m.FileName) {
+		content, err = file.Fetch(m.FileName)
+	} else if m.FileName != "" {
+		content, err = readFile(m.FileName, m.Separator)
+	} else if m.Stream {
+		content, err = m.readStream()
 	} else {
 		content, err = readStdin()
 	}
@@ -90,74 +752,463 @@ func (m *Model) Load() error {
 	if err != nil {
 		return err
 	}
+	m.baseDir = baseDir(m.FileName, m.Clipboard)
+	m.baseURL = baseURL(m.FileName)
 
-	content = strings.TrimPrefix(content, strings.TrimPrefix(delimiter, "\n"))
-	slides := strings.Split(content, delimiter)
+	if asciidoc.IsAsciiDoc(m.FileName) {
+		content = asciidoc.ToMarkdown(content, delimiter)
+	}
 
-	metaData, exists := meta.New().Parse(slides[0])
 	// If the user specifies a custom configuration options
 	// skip the first "slide" since this is all configuration
-	if exists && len(slides) > 1 {
-		slides = slides[1:]
+	header, content, exists := splitHeader(content)
+	m.hasHeader = exists
+	metaData, _ := meta.New().Parse(header)
+	bindings, err := navigation.ParseBindings(metaData.Keys)
+	if err != nil {
+		return err
+	}
+	m.bindings = bindings
+
+	// AsciiDoc page breaks are converted to the default separator
+	m.separator = separator(m.Separator, metaData.Separator)
+	if asciidoc.IsAsciiDoc(m.FileName) {
+		m.separator = delimiter
+	}
+	content = strings.TrimPrefix(content, strings.TrimPrefix(m.separator, "\n"))
+	slides := strings.Split(content, m.separator)
+	// While streaming, the slide after the last separator which arrived may
+	// not have arrived yet
+	if m.stream != nil && len(slides) > 1 && strings.TrimSpace(slides[len(slides)-1]) == "" {
+		slides = slides[:len(slides)-1]
+	}
+
+	if m.Capabilities == nil {
+		m.Capabilities = capability.Detect()
+	}
+
+	vars := map[string]string{}
+	for name, value := range metaData.Vars {
+		vars[name] = value
 	}
+	for name, value := range m.Vars {
+		vars[name] = value
+	}
+
+	for i, slide := range slides {
+		slide = tmpl.Substitute(slide, vars)
+		slide = capability.Apply(slide, m.Capabilities)
+		// Expanding tabs here keeps code blocks consistent between what is
+		// rendered and what is executed
+		slides[i] = code.ExpandTabs(slide, metaData.TabWidth)
+	}
+
+	info := contact.Info{
+		Author:  metaData.Author,
+		Contact: metaData.Contact,
+		Email:   metaData.Email,
+		Social:  metaData.Social,
+	}
+	if !m.NoContact && !info.Empty() {
+		slides = append(slides, contact.Slide(info))
+	}
+
+	// A deck without any content, such as an empty file or a file with only
+	// metadata, shows a placeholder instead of an empty slide
+	if isBlank(slides) {
+		slides = []string{noSlides}
+	}
+
+	// Slides separated by the vertical delimiter itself have no vertical
+	// slides
+	m.vertical = navigation.Tree{TotalSlides: len(slides)}
+	if m.separator != navigation.VerticalDelimiter {
+		slides, m.vertical = navigation.Vertical(slides)
+	}
+
+	// Tables of contents are generated on every load so that they stay in
+	// sync with the slides
+	toc := outline.TOC(slides)
+	for i, slide := range slides {
+		if outline.IsTOC(slide) {
+			slides[i] = toc
+		}
+	}
+
+	m.keys = make([]navigation.KeyMap, len(slides))
+	m.notes = make([][]string, len(slides))
+	for i, slide := range slides {
+		m.keys[i] = navigation.ParseKeyMap(directive.All(slide, "key"))
+		m.notes[i] = directive.All(slide, "note")
+	}
+
+	// Slides which haven't changed since they were last loaded keep their
+	// rendered content, unless the metadata changes how every slide renders
+	if m.rendered == nil || header != m.header || len(slides) != len(m.Slides) {
+		m.rendered = map[renderKey]string{}
+		m.placements = map[renderKey][]images.Placement{}
+		m.liveSlides = map[liveKey]liveSlide{}
+	} else {
+		for key := range m.rendered {
+			if key.page >= len(slides) || key.content != slides[key.page] {
+				delete(m.rendered, key)
+			}
+		}
+		for key := range m.placements {
+			if key.page >= len(slides) || key.content != slides[key.page] {
+				delete(m.placements, key)
+			}
+		}
+		for key := range m.liveSlides {
+			if key.page >= len(slides) || key.content != slides[key.page] {
+				delete(m.liveSlides, key)
+			}
+		}
+	}
+	// The output of the commands of live slides may change between reloads
+	m.entered = true
+	m.header = header
 
 	m.Slides = slides
+	m.words = make([]int, len(slides))
+	for i, slide := range slides {
+		m.words[i] = pace.Words(slide)
+	}
+	// Reloading may remove the slide being presented
+	m.Page = max(0, min(m.Page, len(slides)-1))
+	m.sections = outline.Sections(slides)
 	m.Author = metaData.Author
 	m.Date = time.Now().Format(metaData.Date)
+	m.dateLayout = metaData.Date
 	m.Paging = metaData.Paging
+	m.Progress = metaData.Progress
+	m.Background = m.resolveAsset(metaData.Background)
+	m.Ascii = metaData.Ascii
+	m.Templates = metaData.Templates
+	m.AllowExec = metaData.AllowExec || m.Exec
+	m.AutoPaginate = metaData.AutoPaginate
+	m.Fit = metaData.Fit
+	m.ConfirmQuit = metaData.ConfirmQuit
+	m.SaveBookmarks = metaData.SaveBookmarks
+	if path := m.bookmarkFile(); path != "" {
+		m.bookmarks = bookmark.Load(path)
+	}
+	m.bookmarks = m.bookmarks.Within(len(m.Slides))
+	m.ExtendedSyntax = metaData.ExtendedSyntax
+	m.Diagrams = metaData.Diagrams
+	m.Tree = metaData.Tree
+	m.QuotePanels = metaData.QuotePanels
+	m.IntroDelay = metaData.IntroDelay
+	m.TypeTarget = metaData.TypeTarget
+	m.Canvas = metaData.Canvas
+	m.Timer = metaData.Timer
+	m.Duration = metaData.Duration
+	m.CodeRunners = metaData.CodeRunners
+	m.CodeTimeout = metaData.CodeTimeout
+	m.CodeLog = m.resolve(metaData.CodeLog)
+	m.CodeDir = m.resolve(metaData.CodeDir)
+	m.CodeEnv = metaData.CodeEnv
+	m.WordWrap = metaData.WordWrap
+	m.Align = metaData.Align
+	switch m.Align {
+	case "", "left", "center", "right":
+	default:
+		m.VirtualText = fmt.Sprintf("\nAlignment %q not found, aligning slides to the left", m.Align)
+		m.Align = ""
+	}
+	m.Padding = metaData.Padding
+	if _, _, ok := styles.ParsePadding(m.Padding); m.Padding != "" && !ok {
+		m.VirtualText = fmt.Sprintf("\nPadding %q is not one or two non-negative numbers, using the default padding", m.Padding)
+		m.Padding = ""
+	}
+	m.CodeTheme = metaData.CodeTheme
+	if m.CodeTheme != "" && !styles.CodeThemeExists(m.CodeTheme) {
+		m.VirtualText = fmt.Sprintf("\nCode theme %q not found, using the default code theme", m.CodeTheme)
+		m.CodeTheme = ""
+	}
+	m.AutoAdvance = metaData.AutoAdvance
+	m.Header = metaData.Header
+	// The chrome is toggled while presenting, so reloading only changes it
+	// when the metadata changes
+	if metaData.HideChrome != m.HideChrome {
+		m.HideChrome = metaData.HideChrome
+		m.chromeHidden = m.HideChrome
+	}
+	m.Footer = metaData.Footer
+	m.SlideFilter = metaData.SlideFilter
+	m.filtered = map[string]string{}
+	if m.SlideFilter != "" && !m.AllowExec {
+		m.VirtualText = "\nslideFilter is ignored unless allowExec is set"
+	}
+	m.OnSlide = metaData.OnSlide
+	if m.OnSlide != "" && !m.AllowExec {
+		m.VirtualText = "\nonSlide is ignored unless allowExec is set"
+	}
+	// The errors of the hook are received for as long as slides are
+	// presented, so the channel outlives reloads
+	if m.hookErrors == nil {
+		m.hookErrors = make(chan error, 1)
+	}
+	m.Transition = metaData.Transition
+	m.TransitionScope = metaData.TransitionScope
+	m.TypeDelay = metaData.TypeDelay
+	m.watch = metaData.Watch
+	m.WatchInterval = metaData.WatchInterval
+	m.WordsPerMinute = metaData.WordsPerMinute
+	m.Search.Customize(metaData.SearchPrompt, metaData.SearchPlaceholder, metaData.SearchColor)
+	m.graphics = graphics.Detect()
+	if m.Plain {
+		m.graphics = graphics.None
+	}
+	m.backgrounds = map[string]string{}
+	if m.graphics != graphics.None && m.remoteImages() {
+		m.VirtualText = "\nImages fetched from a URL are not drawn"
+	}
+	m.runs = map[int]runStatus{}
 	if m.Theme == nil {
-		m.Theme = styles.SelectTheme(metaData.Theme)
+		theme := metaData.Theme
+		// ThemeName is set before loading when a theme is given on the
+		// command line, which takes precedence over the metadata
+		if m.ThemeName != "" {
+			theme = m.ThemeName
+		}
+		if !styles.Exists(m.resolveTheme(theme)) {
+			m.VirtualText = fmt.Sprintf("\nTheme %q not found, using the default theme", theme)
+			theme = "default"
+		}
+		m.Theme = styles.SelectTheme(m.resolveTheme(theme))
+		m.ThemeName = theme
+		m.customTheme = theme
+		m.themeModTime, _ = file.ModTime(m.themeFile())
 	}
 	m.content = m.renderSlideContent(slides[0])
+	m.announce()
+	m.syncNotes()
+	if m.Presenter && m.Page < len(m.Slides) {
+		fmt.Fprint(os.Stderr, m.presenterNotes())
+	}
 	return nil
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	page := m.Page
+	model, cmd := m.update(msg)
+	m = model.(Model)
+	if m.Page != page {
+		m.entered = true
+	}
+	if refresh := m.refresh(); refresh != nil {
+		cmd = tea.Batch(cmd, refresh)
+	}
+	return m, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
+	page := m.Page
+	drawn := m.graphicsState()
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		headerHeight := lipgloss.Height(m.headerView())
-		footerHeight := lipgloss.Height(m.footerView())
-		verticalMarginHeight := headerHeight + footerHeight
-
+		m.screenWidth, m.screenHeight = msg.Width, msg.Height
 		if !m.ready {
-			m.viewport = viewport.New(msg.Width, msg.Height-verticalMarginHeight-3)
-			m.viewport.YPosition = headerHeight
-			m.viewport.SetContent(m.renderSlideContent(m.Slides[m.Page]))
+			m.viewport = viewport.New(0, 0)
+			m.resize()
+			m.viewport.SetContent(m.slideContent())
 			m.ready = true
+			m.start = time.Now()
+			m.interactive = m.start.Add(m.introDelay())
+			m.prerendering = true
+			m.prerenders = m.prerender()
+			// The current slide and slides which are always rendered again
+			// are left out, the channel has room for the rest
+			m.prerendered = len(m.Slides) - cap(m.prerenders)
+			cmds = append(cmds, prerenderCmd(m.prerenders))
 		} else {
-			m.viewport.Width = msg.Width
-			m.viewport.Height = msg.Height - verticalMarginHeight
+			width := m.viewport.Width
+			m.resize()
+			if m.viewport.Width != width {
+				m.rendered = map[renderKey]string{}
+				m.placements = map[renderKey][]images.Placement{}
+				m.liveSlides = map[liveKey]liveSlide{}
+			}
 		}
 		m.viewport, cmd = m.viewport.Update(msg)
 		cmds = append(cmds, cmd)
+		m.syncNotes()
+
+	case tea.MouseMsg:
+		if m.annotating || m.Search.Active || m.Finder.Active || m.overview || m.about {
+			return m, nil
+		}
+		// Clicks and scrolling past either end of the slide navigate like
+		// the arrow keys
+		switch msg.Type {
+		case tea.MouseLeft:
+			return m.Update(tea.KeyMsg{Type: tea.KeyRight})
+		case tea.MouseRight:
+			return m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+		case tea.MouseWheelDown:
+			if m.viewport.AtBottom() {
+				return m.Update(tea.KeyMsg{Type: tea.KeyRight})
+			}
+		case tea.MouseWheelUp:
+			if m.viewport.AtTop() {
+				return m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+			}
+		}
+
+	case refreshedMsg:
+		m.refreshing = false
+		if m.liveSlides != nil {
+			for _, slide := range msg {
+				m.liveSlides[slide.key.live()] = slide
+			}
+		}
+
+	case graphicsMsg:
+		// The sequences are written whole on the writer frames are written
+		// on, and frames are written whole too, so neither is written in
+		// the middle of the other
+		_, _ = io.WriteString(m.writer(), msg.seq)
+		return m, nil
+
+	case prerenderedMsg:
+		// Slides which changed or were resized while they were rendered
+		// are left to be rendered again
+		key := msg.key
+		if m.rendered != nil && key.page < len(m.Slides) && key.content == m.Slides[key.page] && key.width == m.viewport.Width {
+			m.rendered[key] = msg.slide
+			if len(msg.placements) > 0 {
+				m.placements[key] = msg.placements
+			}
+		}
+		m.prerendered++
+		return m, prerenderCmd(m.prerenders)
+
+	case prerenderDoneMsg:
+		m.prerendering = false
 
 	case tea.KeyMsg:
 		keyPress := msg.String()
 
+		// Keys bound to actions act as the default keys of the actions,
+		// except while typing
+		if !m.annotating && !m.Search.Active && !m.Finder.Active {
+			keyPress = m.bindings.Resolve(keyPress)
+		}
+
+		// Presenting by hand pauses auto-advancing until it is resumed
+		if m.AutoAdvance > 0 {
+			if keyPress == "P" && !m.annotating && !m.Search.Active && !m.Finder.Active {
+				m.paused = false
+				m.advanceID++
+				return m, advanceCmd(m.advanceID, m.AutoAdvance)
+			}
+			m.paused = true
+		}
+
+		if m.annotating {
+			switch msg.Type {
+			case tea.KeyEnter:
+				err := m.annotate(m.annotation.Value())
+				if err != nil {
+					cmd = m.notify("Error: " + err.Error())
+				} else {
+					cmd = m.notify("Note added to slide")
+				}
+				m.annotating = false
+				return m, cmd
+			case tea.KeyCtrlC, tea.KeyEscape:
+				m.annotating = false
+			default:
+				var cmd tea.Cmd
+				m.annotation, cmd = m.annotation.Update(msg)
+				return m, cmd
+			}
+			return m, nil
+		}
+
+		if m.Finder.Active {
+			switch keyPress {
+			case "enter":
+				if page, ok := m.Finder.Selected(); ok && !m.locked {
+					m.SetPage(page)
+					m.viewport.SetContent(m.slideContent())
+				}
+				m.Finder.Done()
+			case "esc", "ctrl+c":
+				m.Finder.Done()
+			case "up", "ctrl+p":
+				m.Finder.Move(-1)
+			case "down", "ctrl+n":
+				m.Finder.Move(1)
+			default:
+				var cmd tea.Cmd
+				m.Finder.Input, cmd = m.Finder.Input.Update(msg)
+				m.Finder.Reset()
+				return m, cmd
+			}
+			if m.graphicsState() != drawn {
+				return m, m.drawGraphics()
+			}
+			return m, nil
+		}
+
+		if m.about {
+			// Any key dismisses the information panel
+			m.about = false
+			return m, m.drawGraphics()
+		}
+
+		if m.overview {
+			switch keyPress {
+			case "enter":
+				if !m.locked {
+					m.SetPage(m.selected)
+					m.viewport.SetContent(m.slideContent())
+				}
+				m.overview = false
+			case "tab", "esc":
+				m.overview = false
+			case "ctrl+c":
+				return m, tea.Quit
+			case "q":
+				return m, m.quit(msg.String())
+			default:
+				m.selected = overview.Move(m.selected, len(m.Slides), overview.Columns(m.viewport.Width), keyPress)
+			}
+			if m.graphicsState() != drawn {
+				return m, m.drawGraphics()
+			}
+			return m, nil
+		}
+
 		if m.Search.Active {
 
 			switch msg.Type {
 			case tea.KeyEnter:
 				// execute current buffer
 				if m.Search.Query() != "" {
-					m.Search.Execute(&m)
+					cmd = m.notify(m.Search.Execute(&m))
 				} else {
 					m.Search.Done()
 				}
 				// cancel search
-				return m, nil
+				return m, cmd
 			case tea.KeyCtrlC, tea.KeyEscape:
 				// quit command mode
 				m.Search.SetQuery("")
+				m.Search.Clear()
 				m.Search.Done()
 				return m, nil
 			}
 
+			// Every other key is typed into the query rather than acting on
+			// the slides
 			var cmd tea.Cmd
 			m.Search.SearchTextInput, cmd = m.Search.SearchTextInput.Update(msg)
-			cmds = append(cmds, cmd)
+			return m, cmd
 		}
 
 		switch keyPress {
@@ -166,25 +1217,199 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Search.Begin()
 			m.Search.SearchTextInput.Focus()
 			return m, nil
+		case "ctrl+f":
+			// Find a slide to go to by its title
+			titles := make([]string, len(m.Slides))
+			for i, slide := range m.Slides {
+				titles[i] = outline.Title(slide)
+			}
+			m.Finder.Begin(titles)
+			return m, m.drawGraphics()
 		case "ctrl+n":
 			// Go to next occurrence
-			m.Search.Execute(&m)
+			if !m.locked {
+				cmds = append(cmds, m.notify(m.Search.Execute(&m)))
+			}
+		case "ctrl+p":
+			// Go to previous occurrence
+			if !m.locked {
+				cmds = append(cmds, m.notify(m.Search.ExecuteBackward(&m)))
+			}
+		case "L":
+			// Lock navigation on the current slide
+			m.locked = !m.locked
+		case "m":
+			// Bookmark the current slide, or remove its bookmark
+			m.bookmarks = m.bookmarks.Toggle(m.Page)
+			message := fmt.Sprintf("Bookmarked slide %d", m.Page+1)
+			if !m.bookmarks.Has(m.Page) {
+				message = fmt.Sprintf("Removed the bookmark of slide %d", m.Page+1)
+			}
+			if path := m.bookmarkFile(); path != "" {
+				if err := bookmark.Save(path, m.bookmarks); err != nil {
+					message = "Error: could not save the bookmarks: " + err.Error()
+				}
+			}
+			cmds = append(cmds, m.notify(message))
+		case "'", "\"":
+			// Go to the next or previous bookmarked slide
+			if m.locked {
+				break
+			}
+			next := m.bookmarks.Next
+			if keyPress == "\"" {
+				next = m.bookmarks.Previous
+			}
+			page, ok := next(m.Page)
+			if !ok {
+				cmds = append(cmds, m.notify("No slides are bookmarked"))
+				break
+			}
+			m.SetPage(page)
+			m.viewport.SetContent(m.slideContent())
 		case "ctrl+e":
 			// Run code blocks
+			if !m.AllowExec {
+				cmds = append(cmds, m.notify(execDisabled))
+				break
+			}
+			m.output = nil
+			m.VirtualText = m.runCode()
+		case "ctrl+y":
+			// Copy the code blocks to the clipboard
+			cmds = append(cmds, m.notify(m.copyCode()))
+		case "ctrl+t":
+			// Type the code blocks into the tmux pane
+			if !m.AllowExec {
+				cmds = append(cmds, m.notify("Error: typing code requires --exec or allowExec"))
+				break
+			}
+			blocks, err := code.Parse(m.Slides[m.Page])
+			if err != nil {
+				cmds = append(cmds, m.notify("Error: "+err.Error()))
+				break
+			}
+			cmds = append(cmds, m.notify("Typing into "+m.TypeTarget+"..."), m.typeCode(blocks))
+		case "e":
+			// Run code blocks and reveal their output one line at a time
+			if !m.AllowExec {
+				cmds = append(cmds, m.notify(execDisabled))
+				break
+			}
+			if m.output == nil {
+				m.output = strings.Split(m.runCode(), "\n")
+				m.revealed = 0
+			}
+			if m.revealed < len(m.output) {
+				m.revealed++
+			}
+			m.VirtualText = strings.Join(m.output[:m.revealed], "\n")
+		case "A":
+			// Prompt for a note to add to the slide's source
+			m.annotation = textinput.NewModel()
+			m.annotation.Prompt = "todo: "
+			m.annotation.PromptStyle = styles.Search
+			m.annotation.TextStyle = styles.Search
+			m.annotation.Focus()
+			m.annotating = true
+			return m, nil
+		case "f":
+			// Focus the next code block of the slide, leaving focus after
+			// the last one
+			blocks, err := code.Parse(m.Slides[m.Page])
+			if err != nil {
+				break
+			}
+			m.focus = (m.focus + 1) % (len(blocks) + 1)
+			m.viewport.SetContent(m.slideContent())
+			m.viewport.GotoTop()
+		case "]", "[":
+			// Select the next or previous code block of the slide, selecting
+			// every block again after the last and before the first one
 			blocks, err := code.Parse(m.Slides[m.Page])
 			if err != nil {
-				// We couldn't parse the code block on the screen
-				m.VirtualText = "\n" + err.Error()
+				break
+			}
+			step := 1
+			if keyPress == "[" {
+				step = len(blocks)
+			}
+			m.selectedBlock = (m.selectedBlock + step) % (len(blocks) + 1)
+			m.output = nil
+		case "esc":
+			m.focus = 0
+			m.Search.Clear()
+		case "s":
+			// Toggle the speaker notes of the slide
+			m.showNotes = !m.showNotes
+			m.syncNotes()
+		case "J", "K":
+			// Scroll the speaker notes without scrolling the slide, or the
+			// slide itself when the notes are hidden
+			viewport := &m.viewport
+			if m.showNotes {
+				viewport = &m.notesViewport
+			}
+			if keyPress == "J" {
+				viewport.LineDown(1)
+			} else {
+				viewport.LineUp(1)
 			}
-			var outs []string
-			for _, block := range blocks {
-				res := code.Execute(block)
-				outs = append(outs, res.Out)
+		case "i":
+			m.about = true
+		case "tab":
+			// Show a grid of all slides to pick one from
+			m.overview = true
+			m.selected = m.Page
+		case "c":
+			// Toggle the header, footer and status bar
+			m.chromeHidden = !m.chromeHidden
+			m.resize()
+			m.viewport.SetContent(m.slideContent())
+		case "o":
+			// Toggle the agenda sidebar
+			m.agenda = !m.agenda
+			m.viewport.Width = m.width - m.sidebarWidth()
+			m.viewport.SetContent(m.slideContent())
+			// The agenda shows the elapsed time
+			if m.agenda {
+				m.timerID++
+				cmds = append(cmds, timerCmd(m.timerID))
 			}
-			m.VirtualText = strings.Join(outs, "\n")
-		case "ctrl+c", "q":
+		case "t":
+			// Cycle through the themes, re-rendering the current slide
+			m.ThemeName = m.nextTheme()
+			m.Theme = styles.SelectTheme(m.resolveTheme(m.ThemeName))
+			cmds = append(cmds, m.notify("Theme: "+m.ThemeName))
+		case "ctrl+c":
 			return m, tea.Quit
+		case "q":
+			return m, m.quit(msg.String())
 		default:
+			if m.locked || time.Now().Before(m.interactive) {
+				break
+			}
+			if page, ok := m.keys[m.Page].Target(keyPress, len(m.Slides)); ok {
+				m.buffer = ""
+				m.SetPage(page)
+				m.viewport.SetContent(m.slideContent())
+				break
+			}
+			if m.treeNavigation() && m.buffer == "" {
+				if page, ok := m.tree().Navigate(m.Page, keyPress); ok {
+					m.SetPage(page)
+					m.viewport.SetContent(m.slideContent())
+					break
+				}
+			}
+			if m.buffer == "" && m.turnFragment(navigation.Direction(keyPress)) {
+				m.viewport.SetContent(m.slideContent())
+				break
+			}
+			if m.AutoPaginate && m.buffer == "" && m.turnSubPage(navigation.Direction(keyPress)) {
+				m.viewport.SetContent(m.slideContent())
+				break
+			}
 			newState := navigation.Navigate(navigation.State{
 				Buffer:      m.buffer,
 				Page:        m.Page,
@@ -192,93 +1417,1176 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}, keyPress)
 			m.buffer = newState.Buffer
 			m.SetPage(newState.Page)
-			m.viewport.SetContent(m.renderSlideContent(m.Slides[m.Page]))
+			if m.Page != page && navigation.Direction(keyPress) == navigation.Previous {
+				// Going back shows the previous slide as it was left, with
+				// all of its fragments revealed
+				m.fragment = fragment.Count(m.Slides[m.Page]) - 1
+			}
+			m.viewport.SetContent(m.slideContent())
 		}
 
 	case fileWatchMsg:
-		newFileInfo, err := os.Stat(m.FileName)
-		if err == nil && newFileInfo.ModTime() != fileInfo.ModTime() {
-			fileInfo = newFileInfo
-			_ = m.Load()
-			if m.Page >= len(m.Slides) {
-				m.Page = len(m.Slides) - 1
+		// Editors which replace the file when saving may leave the slides
+		// missing for a moment, they are checked again on the next tick
+		newModTime, err := file.ModTime(m.FileName)
+		if err == nil && !newModTime.Equal(modTime) {
+			modTime = newModTime
+			cmds = append(cmds, fileSettledCmd(newModTime))
+		}
+		// The file of a custom theme is watched too, so that themes can be
+		// edited while presenting
+		if path := m.themeFile(); path != "" {
+			newModTime, err := file.ModTime(path)
+			if err == nil && !newModTime.Equal(m.themeModTime) {
+				m.themeModTime = newModTime
+				cmds = append(cmds, themeSettledCmd(newModTime))
 			}
 		}
-		cmds = append(cmds, fileWatchCmd())
-	}
-	m.viewport, cmd = m.viewport.Update(msg)
-	cmds = append(cmds, cmd)
-	return m, tea.Batch(cmds...)
-}
+		cmds = append(cmds, fileWatchCmd(m.watchInterval()))
 
-func (m Model) View() string {
-	if !m.ready {
-		return "\n initializing..."
+	case fileSettledMsg:
+		newModTime, err := file.ModTime(m.FileName)
+		switch {
+		case err != nil:
+			// The slides are reloaded once they are back
+		case newModTime.Equal(msg.modTime):
+			cmds = append(cmds, m.reload())
+		case !newModTime.Equal(modTime):
+			// The slides are still being written
+			modTime = newModTime
+			cmds = append(cmds, fileSettledCmd(newModTime))
+		}
+
+	case themeSettledMsg:
+		newModTime, err := file.ModTime(m.themeFile())
+		switch {
+		case err != nil:
+			// The theme is reloaded once it is back
+		case newModTime.Equal(msg.modTime):
+			m.Theme = styles.SelectTheme(m.ThemePath())
+			m.rendered = map[renderKey]string{}
+			if m.ready {
+				m.viewport.SetContent(m.slideContent())
+			}
+			cmds = append(cmds, m.notify("Theme reloaded"))
+		case !newModTime.Equal(m.themeModTime):
+			// The theme is still being written
+			m.themeModTime = newModTime
+			cmds = append(cmds, themeSettledCmd(newModTime))
+		}
+
+	case timerMsg:
+		if msg.id != m.timerID {
+			break
+		}
+		m.ticked = true
+		if m.ticks() {
+			cmds = append(cmds, timerCmd(m.timerID))
+		}
+
+	case followMsg:
+		if page := msg.Page - 1; page >= 0 && page < len(m.Slides) {
+			m.SetPage(page)
+			m.viewport.SetContent(m.slideContent())
+		}
+		cmds = append(cmds, followCmd(m.Follow))
+
+	case streamMsg:
+		if msg.done {
+			break
+		}
+		m.streamed = msg.content
+		cmds = append(cmds, m.reload(), streamCmd(m.stream))
+
+	case hookErrorMsg:
+		cmds = append(cmds, m.notify("Error: onSlide failed: "+msg.err.Error()), hookErrorCmd(m.hookErrors))
+
+	case advanceMsg:
+		if msg.id != m.advanceID || m.paused {
+			break
+		}
+		m.SetPage((m.Page + 1) % len(m.Slides))
+		m.viewport.SetContent(m.slideContent())
+		cmds = append(cmds, advanceCmd(m.advanceID, m.AutoAdvance))
+
+	case transitionMsg:
+		if msg.id == m.transitionID && m.transitionFrame > 0 {
+			m.transitionFrame++
+			if m.transitionFrame >= transition.Frames {
+				m.transitionFrame = 0
+			} else {
+				cmds = append(cmds, transitionCmd(m.transitionID))
+			}
+		}
+
+	case typedMsg:
+		if msg.err != nil {
+			cmds = append(cmds, m.notify("Error: "+msg.err.Error()))
+		}
+
+	case toastMsg:
+		if msg.id == m.toastID {
+			m.toast = ""
+		}
+
+	case clipboardWatchMsg:
+		content, err := clipboard.ReadAll()
+		if err == nil && content != m.clipboard && strings.TrimSpace(content) != "" {
+			cmds = append(cmds, m.reload())
+		}
+		cmds = append(cmds, clipboardWatchCmd())
+	}
+	if m.Page != page && m.animates(page) {
+		cmds = append(cmds, m.startTransition(page))
+	}
+	m.viewport, cmd = m.viewport.Update(msg)
+	cmds = append(cmds, cmd)
+	if m.graphicsState() != drawn {
+		cmds = append(cmds, m.drawGraphics())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// graphicsState is everything which changes the images drawn on the screen,
+// the images are redrawn whenever it changes
+type graphicsState struct {
+	page      int
+	fragment  int
+	focus     int
+	offset    int
+	width     int
+	height    int
+	overview  bool
+	finding   bool
+	about     bool
+	animating bool
+	shown     bool
+	images    int
+}
+
+func (m Model) graphicsState() graphicsState {
+	return graphicsState{
+		page:      m.Page,
+		fragment:  m.fragment,
+		focus:     m.focus,
+		offset:    m.viewport.YOffset,
+		width:     m.viewport.Width,
+		height:    m.viewport.Height,
+		overview:  m.overview,
+		finding:   m.Finder.Active,
+		about:     m.about,
+		animating: m.transitionFrame > 0,
+		shown:     m.ready,
+		images:    len(m.currentPlacements()),
+	}
+}
+
+const (
+	// sidebarWidth is the width of the agenda sidebar
+	sidebarWidth = 32
+	// minSidebarWidth is the terminal width below which the sidebar is
+	// hidden to leave enough room for the slides
+	minSidebarWidth = 100
+)
+
+// sidebarWidth returns the width taken by the agenda sidebar or the panel of
+// the presenter console, which is zero when neither is shown
+func (m Model) sidebarWidth() int {
+	if m.width < minSidebarWidth {
+		return 0
+	}
+	if m.Console {
+		return m.width / 3
+	}
+	if !m.agenda {
+		return 0
+	}
+	return sidebarWidth
+}
+
+// execDisabled explains how to run code blocks when running them isn't
+// allowed
+const execDisabled = "Error: running code is disabled, start slides with --exec or set allowExec: true"
+
+// runCode executes the code blocks of the current slide, records whether
+// they succeeded and returns their output
+func (m *Model) runCode() string {
+	blocks, err := code.Parse(m.Slides[m.Page])
+	if err != nil {
+		// We couldn't parse the code block on the screen
+		return "\n" + err.Error()
+	}
+	blocks = m.selectBlocks(blocks)
+	opts := m.codeOptions()
+	status := runSuccess
+	var outs []string
+	for _, block := range blocks {
+		if _, ok := opts.Language(block.Language); !ok {
+			outs = append(outs, fmt.Sprintf("Error: can't run %s code, add a command for it to codeRunners in the metadata", block.Language))
+			status = runFailure
+			continue
+		}
+		res := code.ExecuteWith(block, opts)
+		outs = append(outs, res.Out)
+		if res.ExitCode != 0 {
+			status = runFailure
+		}
+		if err := m.logRun(block, res); err != nil {
+			outs = append(outs, "Error: could not write to the code log: "+err.Error())
+		}
+	}
+	m.runs[m.Page] = status
+	return strings.Join(outs, "\n")
+}
+
+// logRun appends the code block and its output to the CodeLog, along with
+// when and on which slide it was executed
+func (m Model) logRun(block code.Block, res code.Result) error {
+	if m.CodeLog == "" {
+		return nil
+	}
+	f, err := os.OpenFile(m.CodeLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "--- %s, slide %d, %s (exit code %d)\n%s\n--- output\n%s\n\n",
+		time.Now().Format("2006-01-02 15:04:05"), m.Page+1, block.Language, res.ExitCode,
+		strings.TrimRight(block.Code, "\n"), strings.TrimRight(res.Out, "\n"))
+	return err
+}
+
+// copyCode copies the code blocks of the current slide to the clipboard,
+// separated by blank lines, or only the selected or focused code block
+func (m Model) copyCode() string {
+	blocks, err := code.Parse(m.Slides[m.Page])
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	blocks = m.selectBlocks(blocks)
+	codes := make([]string, len(blocks))
+	for i, block := range blocks {
+		codes[i] = block.Code
+	}
+	if err := clipboard.WriteAll(strings.Join(codes, "\n\n")); err != nil {
+		return "Error: could not copy code: " + err.Error()
+	}
+	return "Copied code to the clipboard"
+}
+
+// codeOptions returns the options code blocks are executed with
+func (m Model) codeOptions() code.Options {
+	runners := map[string]code.Language{}
+	for language, command := range m.CodeRunners {
+		runners[language] = code.Runner(language, command)
+	}
+	env := make([]string, 0, len(m.CodeEnv))
+	for name, value := range m.CodeEnv {
+		env = append(env, name+"="+value)
+	}
+	sort.Strings(env)
+	return code.Options{Runners: runners, Timeout: m.CodeTimeout, Dir: m.CodeDir, Env: env}
+}
+
+// typeCode types the code blocks into the TypeTarget in the background
+func (m Model) typeCode(blocks []code.Block) tea.Cmd {
+	target, delay := m.TypeTarget, m.TypeDelay
+	return func() tea.Msg {
+		for _, block := range blocks {
+			if err := typist.Type(target, block.Code, delay); err != nil {
+				return typedMsg{err: err}
+			}
+		}
+		return typedMsg{}
+	}
+}
+
+// runStatus is the result of the last execution of a slide's code blocks
+type runStatus int
+
+const (
+	notRun runStatus = iota
+	runSuccess
+	runFailure
+)
+
+// selectBlocks returns the selected code block of the slide, or the focused
+// one, falling back to every block when neither is
+func (m Model) selectBlocks(blocks []code.Block) []code.Block {
+	index := m.selectedBlock
+	if index == 0 {
+		index = m.focus
+	}
+	if index > 0 && index <= len(blocks) {
+		return blocks[index-1 : index]
+	}
+	return blocks
+}
+
+// selectionBadge returns a badge showing which code block of the slide is
+// selected, when one is
+func (m Model) selectionBadge() string {
+	if m.selectedBlock == 0 {
+		return ""
+	}
+	blocks, err := code.Parse(m.Slides[m.Page])
+	if err != nil || m.selectedBlock > len(blocks) {
+		return ""
+	}
+	return styles.RunPending.Render(fmt.Sprintf("▶ block %d/%d", m.selectedBlock, len(blocks)))
+}
+
+// runBadge returns a badge showing the result of the last execution of the
+// current slide's code blocks, slides without code blocks have no badge
+func (m Model) runBadge() string {
+	if _, err := code.Parse(m.Slides[m.Page]); err != nil {
+		return ""
+	}
+	switch m.runs[m.Page] {
+	case runSuccess:
+		return styles.RunSuccess.Render("✓ passed")
+	case runFailure:
+		return styles.RunFailure.Render("✗ failed")
+	default:
+		return styles.RunPending.Render("○ not run")
+	}
+}
+
+// nextTheme returns the theme following the current one, cycling through the
+// built-in themes and the custom theme from the metadata
+func (m Model) nextTheme() string {
+	next := styles.NextTheme(m.ThemeName)
+	isCustom := true
+	for _, theme := range styles.Themes {
+		if theme == m.customTheme {
+			isCustom = false
+		}
+	}
+	if isCustom && m.ThemeName != m.customTheme && next == styles.Themes[0] {
+		return m.customTheme
+	}
+	return next
+}
+
+// graphicsDelay is how long after the view changes the images are drawn, so
+// that they are drawn on top of the updated view
+const graphicsDelay = 50 * time.Millisecond
+
+// drawGraphics draws the background image of the current slide behind the
+// viewport, and the images of the slide in the space left for them. Images
+// are written straight to the terminal, outside of the rendered view, so
+// that they are drawn beneath or on top of the text.
+func (m Model) drawGraphics() tea.Cmd {
+	if m.graphics == graphics.None {
+		return nil
+	}
+
+	var seq string
+	// Only kitty is able to draw images beneath text, or to remove them
+	if m.graphics == graphics.Kitty {
+		seq = graphics.Clear(m.graphics)
+		if bg, ok := directive.Get(m.Slides[m.Page], "background"); ok {
+			seq += m.renderBackground(m.resolveAsset(bg))
+		} else if m.Background != "" {
+			seq += m.renderBackground(m.Background)
+		}
+	}
+	seq += m.renderImages()
+	if seq == "" {
+		return nil
+	}
+
+	return tea.Tick(graphicsDelay, func(time.Time) tea.Msg {
+		return graphicsMsg{seq: seq}
+	})
+}
+
+// renderImages returns the escape sequences drawing the images of the slide
+// which are visible in the viewport
+func (m Model) renderImages() string {
+	if m.overview || m.Finder.Active || m.about || m.focus > 0 || m.AutoPaginate || m.transitionFrame > 0 {
+		return ""
+	}
+
+	top, left := m.canvasOffset()
+	var seq strings.Builder
+	// Rendering the slide finds where its images are drawn
+	m.renderSlideContent(m.Slides[m.Page])
+	for _, p := range m.currentPlacements() {
+		row := m.slideStyle().GetPaddingTop() + p.Line - m.viewport.YOffset
+		if row < 0 || row+p.Rows > m.viewport.Height {
+			continue
+		}
+		key := fmt.Sprintf("image:%s:%dx%d", p.Path, p.Cols, p.Rows)
+		image, ok := m.backgrounds[key]
+		if !ok {
+			img, err := graphics.Load(m.resolveAsset(p.Path))
+			if err != nil {
+				continue
+			}
+			image, err = graphics.Encode(m.graphics, img, p.Cols, p.Rows, 0)
+			if err != nil {
+				continue
+			}
+			m.backgrounds[key] = image
+		}
+		seq.WriteString(graphics.Place(m.viewport.YPosition+top+row, left+m.slideStyle().GetPaddingLeft()+p.Col, image))
+	}
+	return seq.String()
+}
+
+func (m Model) renderBackground(path string) string {
+	key := fmt.Sprintf("%s:%dx%d", path, m.viewport.Width, m.viewport.Height)
+	if seq, ok := m.backgrounds[key]; ok {
+		return seq
+	}
+
+	img, err := graphics.Load(path)
+	if err != nil {
+		return ""
+	}
+	// Dim the image so that the text on top of it is still readable
+	seq, err := graphics.Encode(m.graphics, graphics.Dim(img, 0.7), m.viewport.Width, m.viewport.Height, -1)
+	if err != nil {
+		return ""
+	}
+	top, left := m.canvasOffset()
+	seq = graphics.Place(m.viewport.YPosition+top, left, seq)
+	m.backgrounds[key] = seq
+	return seq
+}
+
+func (m Model) View() string {
+	if !m.ready {
+		return "\n initializing..."
+	}
+
+	content := m.slideContent()
+	if m.transitionFrame > 0 {
+		content = transition.Frame(m.Transition, m.transitionFrom, content, m.transitionFrame)
+	}
+	m.viewport.SetContent(content)
+	var left string
+	if m.annotating {
+		// render annotation prompt
+		left = styles.Search.Render(m.annotation.View())
+	} else if m.Search.Active {
+		// render search bar
+		left = m.Search.SearchTextInput.View()
+	} else if m.Finder.Active {
+		left = m.Finder.Input.View()
+	} else if m.toast != "" {
+		left = styles.Toast.Render(m.toast)
+	} else if m.prerendering {
+		left = styles.Author.Render(fmt.Sprintf("initializing (%d/%d)...", m.prerendered, len(m.Slides)))
+	} else {
+		// render author and date
+		left = styles.Author.Render(m.Author) + styles.Date.Render(m.Date)
+	}
+
+	right := m.selectionBadge() + m.runBadge() + styles.Page.Render(m.paging())
+	status := styles.Status.Render(styles.JoinHorizontal(left, right, m.viewport.Width))
+	var notes string
+	if m.showNotes && m.ready {
+		notes = styles.Notes.Copy().Width(m.viewport.Width - 2).Render(m.notesViewport.View())
+		m.viewport.Height = max(1, m.viewport.Height-lipgloss.Height(notes))
+	}
+	body := m.viewport.View()
+	if notes != "" {
+		body = lipgloss.JoinVertical(lipgloss.Left, body, notes)
+	}
+	if m.overview {
+		titles := make([]string, len(m.Slides))
+		for i, slide := range m.Slides {
+			titles[i] = outline.Title(slide)
+		}
+		grid := overview.Render(titles, m.Page, m.selected, m.viewport.Width, m.viewport.Height)
+		body = lipgloss.Place(m.viewport.Width, m.viewport.Height, lipgloss.Center, lipgloss.Top, grid)
+	}
+	if m.Finder.Active {
+		body = lipgloss.Place(m.viewport.Width, m.viewport.Height, lipgloss.Left, lipgloss.Top, m.Finder.View(m.viewport.Height))
+	}
+	if m.about {
+		body = lipgloss.Place(m.viewport.Width, m.viewport.Height, lipgloss.Center, lipgloss.Center, styles.Overlay.Render(m.aboutView()))
+	}
+	if width := m.sidebarWidth(); width > 0 {
+		panel := outline.Agenda(m.sections, m.Page, time.Since(m.start))
+		if m.Console {
+			// The sidebar's border and padding take up 4 columns
+			panel = m.consoleView(width-4, m.viewport.Height)
+		}
+		sidebar := styles.Sidebar.Copy().Width(width - 2).Height(m.viewport.Height).Render(panel)
+		body = lipgloss.JoinHorizontal(lipgloss.Top, body, sidebar)
+	}
+	var view string
+	if m.chromeHidden {
+		// Without the chrome only the search bar, prompts and toasts are
+		// shown
+		if !m.annotating && !m.Search.Active && !m.Finder.Active && m.toast == "" {
+			status = ""
+		}
+		view = body + "\n" + status
+	} else {
+		newContent := fmt.Sprintf("%s\n%s\n%s", m.headerView(), body, m.footerView())
+		view = styles.JoinVertical(newContent, status, m.viewport.Height)
+	}
+	if _, _, ok := styles.ParseSize(m.Canvas); ok {
+		// Fill the canvas so that it is placed where canvasOffset expects
+		width, height := m.canvasSize()
+		view = lipgloss.Place(width, height, lipgloss.Left, lipgloss.Top, view)
+		view = lipgloss.Place(m.screenWidth, m.screenHeight, lipgloss.Center, lipgloss.Center, view,
+			lipgloss.WithWhitespaceChars("·"), lipgloss.WithWhitespaceForeground(styles.CanvasFill))
+	}
+	if m.Plain {
+		view = reStyle.ReplaceAllString(view, "")
+	}
+	return view
+}
+
+// canvasSize returns the size the slides are presented at, which is the size
+// of the canvas as long as it fits in the terminal
+func (m Model) canvasSize() (int, int) {
+	width, height, ok := styles.ParseSize(m.Canvas)
+	if !ok {
+		return m.screenWidth, m.screenHeight
+	}
+	return min(width, m.screenWidth), min(height, m.screenHeight)
+}
+
+// canvasOffset returns the row and column of the top left corner of the
+// canvas in the terminal
+func (m Model) canvasOffset() (int, int) {
+	width, height := m.canvasSize()
+	return (m.screenHeight - height) / 2, (m.screenWidth - width) / 2
+}
+
+// aboutView renders the information about the deck shown in the
+// information panel
+func (m Model) aboutView() string {
+	source := m.FileName
+	switch {
+	case m.Clipboard:
+		source = "clipboard"
+	case source == "":
+		source = "stdin"
+	}
+	rows := [][2]string{
+		{"Title", outline.Title(m.Slides[0])},
+		{"Author", m.Author},
+		{"Date", m.Date},
+		{"Theme", m.ThemeName},
+		{"Slides", strconv.Itoa(len(m.Slides))},
+		{"Talk time", m.talkTime()},
+		{"File", source},
+	}
+	if info, err := os.Stat(m.FileName); err == nil && !m.Clipboard {
+		rows = append(rows, [2]string{"Modified", info.ModTime().Format("2006-01-02 15:04:05")})
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(styles.Selected.Copy().MarginLeft(0).Width(10).Render(row[0]) + row[1])
+	}
+	return b.String()
+}
+
+// talkTime returns the estimated time taken to present the slides and the
+// current slide, from their number of words
+func (m Model) talkTime() string {
+	var total int
+	for _, words := range m.words {
+		total += words
+	}
+	var current int
+	if m.Page < len(m.words) {
+		current = m.words[m.Page]
+	}
+	return fmt.Sprintf("%s, this slide %s (%d words)",
+		pace.Format(pace.Estimate(total, m.WordsPerMinute)),
+		pace.Format(pace.Estimate(current, m.WordsPerMinute)), current)
+}
+
+func (m *Model) paging() string {
+	format := m.Paging
+	if format == progress.Bar {
+		// The progress bar in the footer replaces the page numbers, unless
+		// the terminal is too narrow to draw it
+		format = ""
+		if m.viewport.Width-lipgloss.Width(m.footerInfo()) < progress.MinBarWidth {
+			format = "%d/%d"
+		}
+	}
+
+	var paging string
+	switch strings.Count(format, "%d") {
+	case 2:
+		paging = fmt.Sprintf(format, m.Page+1, len(m.Slides))
+	case 1:
+		paging = fmt.Sprintf(format, m.Page+1)
+	default:
+		paging = format
+	}
+
+	if m.treeNavigation() {
+		section, slide := m.tree().Position(m.Page)
+		paging += fmt.Sprintf(" [%d.%d]", section+1, slide+1)
+	}
+	if n := m.subPages(); n > 1 {
+		paging += fmt.Sprintf(" (%d/%d)", m.subPage+1, n)
+	}
+	return paging
+}
+
+// treeNavigation returns whether the slides are navigated as a tree, which is
+// always the case for slides split into vertical slides
+func (m Model) treeNavigation() bool {
+	return m.Tree || len(m.vertical.Starts) > 0
+}
+
+// tree returns the two dimensional layout of the slides by section, which
+// are the slides split into vertical slides or otherwise the top-level
+// headings
+func (m Model) tree() navigation.Tree {
+	if len(m.vertical.Starts) > 0 {
+		return m.vertical
+	}
+	starts := make([]int, len(m.sections))
+	for i, section := range m.sections {
+		starts[i] = section.Start
+	}
+	return navigation.Tree{Starts: starts, TotalSlides: len(m.Slides)}
+}
+
+// RenderCurrent renders the slide on the current page as it is displayed
+func (m Model) RenderCurrent() string {
+	// Live slides are rendered as they are now, rather than as they were
+	// last rendered in the background
+	m.liveSlides = nil
+	return m.slideContent()
+}
+
+// slideContent returns the rendered content of the current slide to display
+// in the viewport
+func (m Model) slideContent() string {
+	if content, ok := m.focusContent(); ok {
+		return content
+	}
+	content := m.Search.Highlight(m.fit(m.renderSlideContent(m.Slides[m.Page])))
+	if !m.AutoPaginate {
+		return content
+	}
+	pages := styles.Paginate(content, m.viewport.Height)
+	return pages[min(m.subPage, len(pages)-1)]
+}
+
+// animates returns whether changing slides from the page to the current page
+// is animated
+func (m Model) animates(from int) bool {
+	if m.Transition == "" || m.Transition == transition.None || from >= len(m.Slides) {
+		return false
+	}
+	if m.TransitionScope == transition.Sections {
+		return outline.Current(m.sections, from) != outline.Current(m.sections, m.Page)
+	}
+	return true
+}
+
+// startTransition starts a transition from the slide on the page to the
+// current one
+func (m *Model) startTransition(from int) tea.Cmd {
+	previous := *m
+	previous.Page = from
+	previous.VirtualText = ""
+	previous.focus = 0
+	previous.subPage = 0
+	m.transitionFrom = previous.slideContent()
+	m.transitionFrame = 1
+	m.transitionID++
+	return transitionCmd(m.transitionID)
+}
+
+func transitionCmd(id int) tea.Cmd {
+	return tea.Tick(transition.Interval, func(time.Time) tea.Msg {
+		return transitionMsg{id: id}
+	})
+}
+
+// filter pipes the slide through the SlideFilter, as long as commands are
+// allowed. The output is cached since slides are rendered on every update.
+func (m Model) filter(slide string) string {
+	if m.SlideFilter == "" || !m.AllowExec {
+		return slide
+	}
+	if filtered, ok := m.filtered[slide]; ok {
+		return filtered
+	}
+	filtered := process.Filter(slide, m.SlideFilter, m.baseDir)
+	if m.filtered != nil {
+		m.filtered[slide] = filtered
+	}
+	return filtered
+}
+
+// focusBlock returns the markdown of the focused code block of the current
+// slide
+func (m Model) focusBlock() (string, bool) {
+	if m.focus == 0 {
+		return "", false
+	}
+	blocks, err := code.Parse(m.Slides[m.Page])
+	if err != nil || m.focus > len(blocks) {
+		return "", false
+	}
+	block := blocks[m.focus-1]
+	return "```" + block.Language + "\n" + block.Code + "\n```", true
+}
+
+// focusContent returns the rendered focused code block of the current slide,
+// centered in the viewport
+func (m Model) focusContent() (string, bool) {
+	block, ok := m.focusBlock()
+	if !ok {
+		return "", false
+	}
+	content := m.renderSlideContent(block)
+	return lipgloss.Place(m.viewport.Width, m.viewport.Height, lipgloss.Center, lipgloss.Center, content), true
+}
+
+// subPages returns the number of pages the current slide is split into
+func (m Model) subPages() int {
+	if !m.AutoPaginate {
+		return 1
+	}
+	return len(styles.Paginate(m.fit(m.renderSlideContent(m.Slides[m.Page])), m.viewport.Height))
+}
+
+// fit tightens the rendered slide to fit the viewport when fitting slides,
+// slides with images are left as they are since the images are drawn at the
+// lines they were rendered on
+func (m Model) fit(rendered string) string {
+	if !m.Fit || len(m.currentPlacements()) > 0 {
+		return rendered
+	}
+	return styles.Fit(rendered, m.viewport.Height)
+}
+
+// turnFragment reveals the next fragment of the current slide, or hides the
+// last one revealed, and returns whether there was such a fragment
+func (m *Model) turnFragment(direction int) bool {
+	switch direction {
+	case navigation.Next:
+		if m.fragment < fragment.Count(m.Slides[m.Page])-1 {
+			m.fragment++
+			return true
+		}
+	case navigation.Previous:
+		if m.fragment > 0 {
+			m.fragment--
+			return true
+		}
+	}
+	return false
+}
+
+// turnSubPage moves to the next or previous page of the current slide and
+// returns whether there was such a page to move to
+func (m *Model) turnSubPage(direction int) bool {
+	switch direction {
+	case navigation.Next:
+		if m.subPage < m.subPages()-1 {
+			m.subPage++
+			return true
+		}
+	case navigation.Previous:
+		if m.subPage > 0 {
+			m.subPage--
+			return true
+		}
+	}
+	return false
+}
+
+func readFile(path, separator string) (string, error) {
+	s, err := os.Stat(path)
+	if err != nil {
+		return "", errors.New("could not read file")
+	}
+	if s.IsDir() {
+		return readDir(path, separator)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	content := string(b)
+
+	// Pre-process slides if the file is executable to avoid
+	// unintentional code execution when presenting slides
+	if file.IsExecutable(s) {
+		// Remove shebang if file has one
+		if strings.HasPrefix(content, "#!") {
+			content = strings.Join(strings.SplitN(content, "\n", 2)[1:], "\n")
+		}
+
+		content = process.Pre(content, filepath.Dir(path))
+	}
+
+	return content, err
+}
+
+// baseDir returns the directory relative paths in the slides are resolved
+// against: the directory of the file, the directory itself for a directory of
+// slides, or the working directory for slides which weren't read from a file.
+func baseDir(fileName string, fromClipboard bool) string {
+	if fileName == "" || fromClipboard || file.IsURL(fileName) {
+		dir, _ := os.Getwd()
+		return dir
+	}
+	path, err := filepath.Abs(fileName)
+	if err != nil {
+		return ""
+	}
+	if s, err := os.Stat(path); err == nil && s.IsDir() {
+		return path
+	}
+	return filepath.Dir(path)
+}
+
+// resolve returns the path relative to the directory of the slides, rather
+// than the working directory. Absolute paths and URLs are left untouched.
+func (m Model) resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "http") {
+		return path
+	}
+	return filepath.Join(m.baseDir, path)
+}
+
+// baseURL returns the URL of the slides if they are fetched from a URL
+func baseURL(fileName string) *url.URL {
+	if !file.IsURL(fileName) {
+		return nil
+	}
+	u, err := url.Parse(fileName)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// resolveAsset returns the path of an image or a theme resolved like other
+// paths, or relative to the URL of slides fetched from a URL
+func (m Model) resolveAsset(path string) string {
+	if m.baseURL == nil || path == "" || file.IsURL(path) {
+		return m.resolve(path)
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	return m.baseURL.ResolveReference(ref).String()
+}
+
+// remoteImages returns whether any image of the slides, or the background of
+// any of them, is fetched from a URL
+func (m Model) remoteImages() bool {
+	paths := []string{m.Background}
+	for _, slide := range m.Slides {
+		paths = append(paths, directive.All(slide, "background")...)
+		_, pictures := images.Extract(slide)
+		for _, picture := range pictures {
+			paths = append(paths, picture.Path)
+		}
+	}
+	for _, path := range paths {
+		if file.IsURL(m.resolveAsset(path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderError is shown in place of slides which can't be rendered
+const renderError = "Error: Could not render markdown!"
+
+// Check returns the problems of the loaded slides which would otherwise only
+// be noticed while presenting, along with the number of code blocks of the
+// slides
+func (m Model) Check() ([]string, int) {
+	var problems []string
+	if m.VirtualText != "" {
+		problems = append(problems, strings.TrimPrefix(m.VirtualText, "\n"))
+	}
+	if m.hasHeader {
+		if err := meta.Validate(m.header); err != nil {
+			problems = append(problems, "metadata: "+err.Error())
+		}
+	}
+	if len(m.Slides) == 1 && m.Slides[0] == noSlides {
+		problems = append(problems, "no slides found")
+	}
+
+	var blocks int
+	for i, slide := range m.RenderSlides(80) {
+		if strings.Contains(slide, renderError) {
+			problems = append(problems, fmt.Sprintf("slide %d: could not render markdown", i+1))
+		}
+		if code.Unterminated(m.Slides[i]) {
+			problems = append(problems, fmt.Sprintf("slide %d: code block is never closed", i+1))
+		}
+		if parsed, err := code.Parse(m.Slides[i]); err == nil {
+			blocks += len(parsed)
+		}
+	}
+	return problems, blocks
+}
+
+// RenderSlides renders every slide as it is presented, at the given width
+func (m Model) RenderSlides(width int) []string {
+	m.viewport.Width = width
+	// Images are drawn in the terminal, outside of the rendered slides
+	m.graphics = graphics.None
+	m.VirtualText = ""
+	m.liveSlides = nil
+	rendered := make([]string, len(m.Slides))
+	for i, slide := range m.Slides {
+		m.Page = i
+		m.fragment = fragment.Count(slide) - 1
+		rendered[i] = m.renderSlideContent(slide)
+	}
+	return rendered
+}
+
+// ThemePath returns the theme in use, with the path of a custom theme
+// resolved relative to the slides
+func (m Model) ThemePath() string {
+	return m.resolveTheme(m.ThemeName)
+}
+
+// themeFile returns the file of the theme in use, which is empty for the
+// built-in themes and themes fetched from a URL
+func (m Model) themeFile() string {
+	path := m.ThemePath()
+	for _, t := range styles.Themes {
+		if t == path {
+			return ""
+		}
+	}
+	if strings.HasPrefix(path, "http") {
+		return ""
+	}
+	return path
+}
+
+// bookmarkFile returns the file the bookmarks are stored in, which is empty
+// unless SaveBookmarks is set and the slides were read from a file
+func (m Model) bookmarkFile() string {
+	if !m.SaveBookmarks || m.Clipboard || m.FileName == "" || file.IsURL(m.FileName) {
+		return ""
+	}
+	return bookmark.File(m.FileName)
+}
+
+// resolveTheme resolves the path of a custom theme, either named after a
+// theme in ThemesDir or relative to the slides, the names of built-in themes
+// are left untouched
+func (m Model) resolveTheme(theme string) string {
+	for _, t := range styles.Themes {
+		if t == theme {
+			return theme
+		}
+	}
+	if path, ok := styles.FindTheme(m.ThemesDir, theme); ok {
+		return path
+	}
+	return m.resolveAsset(theme)
+}
+
+// annotate adds the note to the source of the current slide, as long as the
+// slides were read from a writable file
+func (m *Model) annotate(note string) error {
+	if m.Clipboard {
+		return errors.New("can not add notes to slides read from the clipboard")
+	}
+	if m.FileName == "" {
+		return errors.New("can not add notes to slides read from stdin")
+	}
+	if file.IsURL(m.FileName) {
+		return errors.New("can not add notes to slides fetched from a URL")
+	}
+	// The slides of AsciiDoc files are converted to markdown, so they can't
+	// be found in the source
+	if asciidoc.IsAsciiDoc(m.FileName) {
+		return errors.New("can not add notes to AsciiDoc slides")
+	}
+	s, err := os.Stat(m.FileName)
+	if err != nil {
+		return errors.New("could not read file")
+	}
+	if s.IsDir() {
+		return errors.New("can not add notes to a directory of slides")
+	}
+	if !file.IsWritable(s) {
+		return errors.New("file is read-only")
+	}
+	b, err := ioutil.ReadFile(m.FileName)
+	if err != nil {
+		return err
 	}
+	content := string(b)
 
-	m.viewport.SetContent(m.renderSlideContent(m.Slides[m.Page]))
-	var left string
-	if m.Search.Active {
-		// render search bar
-		left = m.Search.SearchTextInput.View()
-	} else {
-		// render author and date
-		left = styles.Author.Render(m.Author) + styles.Date.Render(m.Date)
+	// Keep the shebang of executable files in place
+	var shebang string
+	if file.IsExecutable(s) && strings.HasPrefix(content, "#!") {
+		parts := strings.SplitN(content, "\n", 2)
+		shebang = parts[0] + "\n"
+		content = strings.Join(parts[1:], "\n")
 	}
 
-	right := styles.Page.Render(m.paging())
-	status := styles.Status.Render(styles.JoinHorizontal(left, right, m.viewport.Width))
-	newContent := fmt.Sprintf("%s\n%s\n%s", m.headerView(), m.viewport.View(), m.footerView())
-	return styles.JoinVertical(newContent, status, m.viewport.Height)
+	// Notes are added to the slides after the metadata, which may be
+	// separated differently
+	var front string
+	if m.hasHeader {
+		_, slides, ok := splitHeader(content)
+		if !ok {
+			return annotate.ErrNoSlide
+		}
+		front = strings.TrimSuffix(content, slides)
+		content = slides
+	}
+	if len(m.vertical.Starts) > 0 {
+		content, err = m.annotateVertical(content, note)
+	} else {
+		content, err = annotate.Append(content, m.separator, m.Page, note)
+	}
+	if err != nil {
+		return err
+	}
+	return file.Write(m.FileName, []byte(shebang+front+content))
 }
 
-func (m *Model) paging() string {
-	switch strings.Count(m.Paging, "%d") {
-	case 2:
-		return fmt.Sprintf(m.Paging, m.Page+1, len(m.Slides))
-	case 1:
-		return fmt.Sprintf(m.Paging, m.Page+1)
-	default:
-		return m.Paging
+// annotateVertical adds the note to the current slide of slides which are
+// split into vertical slides, the note is added to the vertical slide within
+// its section
+func (m Model) annotateVertical(content, note string) (string, error) {
+	var prefix string
+	if leading := strings.TrimPrefix(m.separator, "\n"); strings.HasPrefix(content, leading) {
+		prefix = leading
+		content = strings.TrimPrefix(content, leading)
+	}
+	sections := strings.Split(content, m.separator)
+	section, slide := m.vertical.Position(m.Page)
+	if section >= len(sections) {
+		return "", annotate.ErrNoSlide
+	}
+	annotated, err := annotate.Append(sections[section], navigation.VerticalDelimiter, slide, note)
+	if err != nil {
+		return "", err
 	}
+	sections[section] = annotated
+	return prefix + strings.Join(sections, m.separator), nil
 }
 
-func readFile(path string) (string, error) {
-	s, err := os.Stat(path)
+func readClipboard() (string, error) {
+	content, err := clipboard.ReadAll()
 	if err != nil {
-		return "", errors.New("could not read file")
+		return "", errors.New("could not read clipboard")
 	}
-	if s.IsDir() {
-		return "", errors.New("can not read directory")
+	if strings.TrimSpace(content) == "" {
+		return "", errors.New("clipboard is empty")
 	}
-	b, err := ioutil.ReadFile(path)
+	return content, nil
+}
+
+// readDir reads the markdown files in the directory, in the order of their
+// names, as a single deck. Only the metadata of the first file applies, the
+// metadata of the other files is dropped. The files are joined with the
+// separator, or the separator of the first file's metadata if it is empty.
+func readDir(path, sep string) (string, error) {
+	paths, err := file.Markdown(path)
 	if err != nil {
-		return "", err
+		return "", errors.New("could not read directory")
 	}
-	content := string(b)
-
-	// Pre-process slides if the file is executable to avoid
-	// unintentional code execution when presenting slides
-	if file.IsExecutable(s) {
-		// Remove shebang if file has one
-		if strings.HasPrefix(content, "#!") {
-			content = strings.Join(strings.SplitN(content, "\n", 2)[1:], "\n")
+	if len(paths) == 0 {
+		return "", errors.New("no markdown files in directory")
+	}
+	decks := make([]string, len(paths))
+	for i, p := range paths {
+		content, err := readFile(p, sep)
+		if err != nil {
+			return "", err
+		}
+		if i == 0 {
+			header, _, _ := splitHeader(content)
+			metaData, _ := meta.New().Parse(header)
+			sep = separator(sep, metaData.Separator)
+		} else {
+			content = stripMeta(content)
 		}
+		decks[i] = strings.Trim(content, "\n")
+	}
+	return strings.Join(decks, sep), nil
+}
 
-		content = process.Pre(content)
+// stripMeta removes the metadata header from the start of the slides
+func stripMeta(content string) string {
+	if !strings.HasPrefix(content, strings.TrimPrefix(delimiter, "\n")) {
+		return content
 	}
+	_, slides, _ := splitHeader(content)
+	return slides
+}
 
-	return content, err
+// splitHeader splits the metadata from the start of the content, returning
+// the metadata, the slides after it and whether there is metadata. The
+// metadata is separated from the slides by the default delimiter even when
+// the slides are separated by another separator. Like YAML front matter,
+// metadata starting with --- may also end with ... instead.
+func splitHeader(content string) (string, string, bool) {
+	fenced := strings.HasPrefix(content, strings.TrimPrefix(delimiter, "\n"))
+	content = strings.TrimPrefix(content, strings.TrimPrefix(delimiter, "\n"))
+	parts := strings.SplitN(content, delimiter, 2)
+	if end := strings.Index(content, documentEnd); fenced && end >= 0 && (len(parts) < 2 || end < len(parts[0])) {
+		parts = []string{content[:end], content[end+len(documentEnd):]}
+	}
+	if len(parts) < 2 {
+		return "", content, false
+	}
+	if _, exists := meta.New().Parse(parts[0]); !exists {
+		return "", content, false
+	}
+	return parts[0], parts[1], true
 }
 
-func readStdin() (string, error) {
-	stat, err := os.Stdin.Stat()
-	if err != nil {
-		return "", err
+// separator returns the separator of the slides as a line of its own, the
+// separator given on the command line takes precedence over the metadata
+func separator(flag, metadata string) string {
+	for _, sep := range []string{flag, metadata} {
+		if sep = strings.Trim(sep, "\n"); sep != "" {
+			return "\n" + sep + "\n"
+		}
 	}
+	return delimiter
+}
 
-	if stat.Mode()&os.ModeNamedPipe == 0 && stat.Size() == 0 {
-		return "", errors.New("no slides provided")
+// noSlides is shown when there are no slides to present
+const noSlides = "# No slides found\n\nSeparate your slides with `---` on a line of its own."
+
+// isBlank returns whether none of the slides have any content
+func isBlank(slides []string) bool {
+	for _, slide := range slides {
+		if strings.TrimSpace(slide) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func readStdin() (string, error) {
+	if err := checkStdin(); err != nil {
+		return "", err
 	}
 
 	reader := bufio.NewReader(os.Stdin)
@@ -298,6 +2606,37 @@ func readStdin() (string, error) {
 	return b.String(), nil
 }
 
+// checkStdin returns an error unless slides are piped or redirected to stdin
+func checkStdin() error {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return err
+	}
+
+	if stat.Mode()&os.ModeNamedPipe == 0 && stat.Size() == 0 {
+		return errors.New("no slides provided")
+	}
+	return nil
+}
+
+// readStream returns the slides piped to stdin so far while streaming. The
+// first time, it starts reading stdin and waits for the first of the slides
+// to arrive.
+func (m *Model) readStream() (string, error) {
+	if m.stream == nil {
+		if err := checkStdin(); err != nil {
+			return "", err
+		}
+		m.stream = file.Stream(os.Stdin)
+		content, ok := <-m.stream
+		if !ok {
+			return "", errors.New("no slides provided")
+		}
+		m.streamed = content
+	}
+	return m.streamed, nil
+}
+
 func (m *Model) CurrentPage() int {
 	return m.Page
 }
@@ -308,7 +2647,158 @@ func (m *Model) SetPage(page int) {
 	}
 
 	m.VirtualText = ""
+	m.output = nil
+	m.revealed = 0
+	m.subPage = 0
+	m.fragment = 0
+	m.focus = 0
+	m.selectedBlock = 0
 	m.Page = page
+
+	if m.Rehearsal != nil {
+		m.Rehearsal.Record(page, time.Now())
+	}
+	m.interactive = time.Now().Add(m.introDelay())
+	m.announce()
+	m.runHook()
+	m.syncNotes()
+	m.notesViewport.GotoTop()
+	if m.Presenter {
+		fmt.Fprint(os.Stderr, m.presenterNotes())
+	}
+}
+
+// currentNotes returns the speaker notes of the current slide
+func (m Model) currentNotes() []string {
+	if m.Page >= len(m.notes) {
+		return nil
+	}
+	return m.notes[m.Page]
+}
+
+// syncNotes sizes the notes viewport to the notes of the current slide,
+// taking up to a third of the slide's height
+func (m *Model) syncNotes() {
+	content := strings.Join(m.currentNotes(), "\n\n")
+	if content == "" {
+		content = "No notes for this slide"
+	}
+	width := max(1, m.viewport.Width-4)
+	content = lipgloss.NewStyle().Width(width).Render(content)
+	m.notesViewport.Width = width
+	m.notesViewport.Height = max(1, min(lipgloss.Height(content), m.viewport.Height/3))
+	m.notesViewport.SetContent(content)
+}
+
+// presenterNotes returns the notes of the current slide as they are printed
+// in presenter mode
+func (m Model) presenterNotes() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "── Slide %d / %d: %s\n", m.Page+1, len(m.Slides), outline.Title(m.Slides[m.Page]))
+	for _, note := range m.currentNotes() {
+		fmt.Fprintf(&b, "%s\n", note)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// preview returns the model rendering the preview of the next slide in a
+// console of the width
+func (m Model) preview(width int) Model {
+	next := m
+	next.Page++
+	next.fragment = 0
+	next.VirtualText = ""
+	next.graphics = graphics.None
+	next.viewport.Width = width - next.slideStyle().GetHorizontalPadding()
+	return next
+}
+
+// consoleView returns the panel of the presenter console, showing the elapsed
+// time, a preview of the next slide and the speaker notes of the current one
+func (m Model) consoleView(width, height int) string {
+	var b strings.Builder
+	elapsed := time.Duration(0)
+	if !m.start.IsZero() {
+		elapsed = time.Since(m.start)
+	}
+	clock, over := timer.Render(elapsed, m.Duration)
+	if over {
+		clock = styles.RunFailure.Copy().MarginRight(0).Render(clock)
+	}
+	b.WriteString(clock + "\n\n")
+
+	b.WriteString(styles.Selected.Copy().MarginLeft(0).Render("Next") + "\n")
+	if m.Page+1 < len(m.Slides) {
+		// The preview takes up at most half of the panel, leaving room for
+		// the notes
+		next := m.preview(width)
+		preview := strings.Split(next.renderSlideContent(next.Slides[next.Page]), "\n")
+		for len(preview) > 1 && strings.TrimSpace(preview[0]) == "" {
+			preview = preview[1:]
+		}
+		if limit := max(1, height/2); len(preview) > limit {
+			preview = preview[:limit]
+		}
+		b.WriteString(strings.Join(preview, "\n"))
+	} else {
+		b.WriteString("End of the slides")
+	}
+
+	b.WriteString("\n\n" + styles.Selected.Copy().MarginLeft(0).Render("Notes") + "\n")
+	notes := m.currentNotes()
+	if len(notes) == 0 {
+		b.WriteString("No notes")
+	}
+	b.WriteString(strings.Join(notes, "\n"))
+	return b.String()
+}
+
+// introDelay returns the intro delay of the current slide, which is set with
+// an <!-- intro: 2s --> directive or defaults to the IntroDelay of the slides
+func (m Model) introDelay() time.Duration {
+	if m.Page >= len(m.Slides) {
+		return m.IntroDelay
+	}
+	value, ok := directive.Get(m.Slides[m.Page], "intro")
+	if !ok {
+		return m.IntroDelay
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return m.IntroDelay
+	}
+	return d
+}
+
+// announce publishes the current slide, as long as the slides are announced
+func (m Model) announce() {
+	if m.Announcer == nil || m.Page >= len(m.Slides) {
+		return
+	}
+	m.Announcer.Announce(announce.Slide{
+		Page:  m.Page + 1,
+		Total: len(m.Slides),
+		Title: outline.Title(m.Slides[m.Page]),
+	})
+}
+
+// runHook runs the OnSlide command for the current slide in the background,
+// errors are dropped while an earlier error is yet to be shown
+func (m Model) runHook() {
+	if m.OnSlide == "" || !m.AllowExec || m.Page >= len(m.Slides) {
+		return
+	}
+	command, dir, page, total, title := m.OnSlide, m.baseDir, m.Page+1, len(m.Slides), outline.Title(m.Slides[m.Page])
+	errs := m.hookErrors
+	go func() {
+		if err := process.Hook(command, dir, page, total, title); err != nil {
+			select {
+			case errs <- err:
+			default:
+			}
+		}
+	}()
 }
 
 func (m *Model) Pages() []string {
@@ -317,17 +2807,135 @@ func (m *Model) Pages() []string {
 
 // pager
 func (m *Model) headerView() string {
-	title := titleStyle.Render("Mr. Pager")
-	line := strings.Repeat("─", max(0, m.viewport.Width-lipgloss.Width(title)))
-	return lipgloss.JoinHorizontal(lipgloss.Center, title, line)
+	style, _, rule := m.chrome()
+	header := m.Header
+	if header == "" {
+		header = "Mr. Pager"
+	}
+	title := style.Render(header)
+	var dots string
+	if m.Progress == progress.Dots {
+		// Keep a few line segments between the title and the dots
+		width := m.viewport.Width - lipgloss.Width(title) - 5
+		dots = " " + progress.RenderDots(m.Page, len(m.Slides), width, m.tree().Starts) + " " + rule
+	}
+	line := strings.Repeat(rule, max(0, m.viewport.Width-lipgloss.Width(title)-lipgloss.Width(dots)))
+	return lipgloss.JoinHorizontal(lipgloss.Center, title, line+dots)
 }
 
 func (m *Model) footerView() string {
-	info := infoStyle.Render(fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100))
-	line := strings.Repeat("─", max(0, m.viewport.Width-lipgloss.Width(info)))
+	style, _, rule := m.chrome()
+	info := m.footerInfo()
+	var brand string
+	if m.Footer != "" {
+		brand = style.Render(m.Footer)
+	}
+	width := max(0, m.viewport.Width-lipgloss.Width(brand)-lipgloss.Width(info))
+	line := strings.Repeat(rule, width)
+	if m.Paging == progress.Bar && width >= progress.MinBarWidth {
+		filled, empty := progress.RenderBar(m.Page, len(m.Slides), width, m.tree().Starts)
+		if m.Ascii {
+			ascii := strings.NewReplacer("━", "=", "─", "-", "│", "|")
+			filled, empty = ascii.Replace(filled), ascii.Replace(empty)
+		}
+		line = styles.ProgressBar.Render(filled) + empty
+	}
+	if brand != "" {
+		return lipgloss.JoinHorizontal(lipgloss.Center, brand, line, info)
+	}
 	return lipgloss.JoinHorizontal(lipgloss.Center, line, info)
 }
 
+// footerInfo returns the box at the right of the footer, showing the scroll
+// position within the slide, whether the slides can run code and when the
+// slides were last reloaded
+func (m *Model) footerInfo() string {
+	_, style, _ := m.chrome()
+	var lock string
+	if m.locked {
+		lock = "locked · "
+	}
+	var exec string
+	if m.AllowExec {
+		exec = "exec · "
+	}
+	var mark string
+	if m.bookmarks.Has(m.Page) {
+		mark = "bookmarked · "
+	}
+	var reloaded string
+	if !m.reloaded.IsZero() {
+		reloaded = "reloaded " + m.reloaded.Format("15:04:05") + " · "
+	}
+	var clock string
+	if m.Timer {
+		elapsed := time.Duration(0)
+		if !m.start.IsZero() {
+			elapsed = time.Since(m.start)
+		}
+		var over bool
+		clock, over = timer.Render(elapsed, m.Duration)
+		if over {
+			clock = styles.RunFailure.Copy().MarginRight(0).Render(clock)
+		}
+		clock += " · "
+	}
+	var more string
+	if !m.viewport.AtBottom() {
+		more = "↓ more below · "
+	}
+	return style.Render(fmt.Sprintf("%s%s%s%s%s%s%3.f%%", exec, mark, lock, reloaded, clock, more, m.viewport.ScrollPercent()*100))
+}
+
+// reload loads the slides again, keeping the scroll position within the
+// current slide and its revealed fragments where the slide still has them.
+// Errors are shown as a toast, which the returned command dismisses.
+func (m *Model) reload() tea.Cmd {
+	offset, revealed := m.viewport.YOffset, m.fragment
+	if err := m.Load(); err != nil {
+		return m.notify("Error: could not reload the slides: " + err.Error())
+	}
+	m.reloaded = time.Now()
+	m.fragment = min(revealed, fragment.Count(m.Slides[m.Page])-1)
+	if !m.ready {
+		return nil
+	}
+	m.resize()
+	m.viewport.SetContent(m.slideContent())
+	m.viewport.SetYOffset(offset)
+	return nil
+}
+
+// resize sizes the viewport to fill the canvas, leaving room for the
+// header, footer and status bar unless they are hidden
+func (m *Model) resize() {
+	width, height := m.canvasSize()
+	m.width = width
+	m.viewport.Width = width - m.sidebarWidth()
+	if m.chromeHidden {
+		// The last line is left for the search bar and prompts
+		m.viewport.YPosition = 0
+		m.viewport.Height = height - 1
+		return
+	}
+	headerHeight := lipgloss.Height(m.headerView())
+	footerHeight := lipgloss.Height(m.footerView())
+	m.viewport.YPosition = headerHeight
+	m.viewport.Height = height - headerHeight - footerHeight - 3
+}
+
+// chrome returns the title and info styles along with the horizontal rule
+// used to draw the header and footer
+func (m *Model) chrome() (lipgloss.Style, lipgloss.Style, string) {
+	if m.Plain {
+		return plainTitleStyle, plainInfoStyle, "-"
+	}
+	if m.Ascii {
+		return asciiTitleStyle, asciiInfoStyle, "-"
+	}
+	return titleStyle, infoStyle, "─"
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -335,13 +2943,359 @@ func max(a, b int) int {
 	return b
 }
 
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// prerenderWorkers is the number of slides rendered at once in the
+// background
+var prerenderWorkers = runtime.NumCPU()
+
+// prerender renders every slide as it is first shown in the background, the
+// slides after the current one first, so that they are cached by the time they
+// are navigated to. Slides are sent on the returned channel as they are
+// rendered, which is closed once every slide has been rendered.
+func (m Model) prerender() <-chan prerenderedMsg {
+	var pages []int
+	for i := 1; i < len(m.Slides); i++ {
+		page := (m.Page + i) % len(m.Slides)
+		if m.live(m.Slides[page]) {
+			continue
+		}
+		pages = append(pages, page)
+	}
+
+	work := make(chan int, len(pages))
+	for _, page := range pages {
+		work <- page
+	}
+	close(work)
+
+	// The channel holds every slide, so that rendering never waits for the
+	// slides to be received
+	prerenders := make(chan prerenderedMsg, len(pages))
+	var wg sync.WaitGroup
+	for i := 0; i < min(prerenderWorkers, len(pages)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range work {
+				// Each slide is rendered by a copy of the model with caches of
+				// its own, since the caches aren't safe to share
+				r := m
+				r.Page, r.fragment, r.VirtualText = page, 0, ""
+				r.rendered, r.placements = nil, nil
+				r.filtered = map[string]string{}
+				slide, placements := r.render(r.Slides[page])
+				prerenders <- prerenderedMsg{key: r.renderKey(r.Slides[page]), slide: slide, placements: placements}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(prerenders)
+	}()
+	return prerenders
+}
+
+// live returns whether the slide shows the time or the output of commands,
+// such slides are rendered again as they are entered and as the time changes
+// rather than going out of date
+func (m Model) live(content string) bool {
+	return tmpl.HasDates(content) || m.Templates && strings.Contains(content, "{{")
+}
+
+// renderKey returns the key the content of the current slide is cached with
+func (m Model) renderKey(content string) renderKey {
+	return renderKey{
+		page:        m.Page,
+		width:       m.viewport.Width,
+		fragment:    m.fragment,
+		content:     content,
+		theme:       m.ThemeName,
+		virtualText: m.VirtualText,
+	}
+}
+
 func (m Model) renderSlideContent(content string) string {
-	r, _ := glamour.NewTermRenderer(m.Theme, glamour.WithWordWrap(m.viewport.Width))
-	slide, err := r.Render(content)
+	key := m.renderKey(content)
+	if m.live(content) {
+		if m.liveSlides == nil {
+			slide, _ := m.render(content)
+			return slide
+		}
+		// Live slides are shown as they were last rendered, nothing is
+		// shown until they are first rendered in the background
+		return m.liveSlides[key.live()].slide
+	}
+	if slide, ok := m.rendered[key]; ok {
+		return slide
+	}
+	slide, placements := m.render(content)
+	if m.rendered != nil {
+		m.rendered[key] = slide
+		if len(placements) > 0 {
+			m.placements[key] = placements
+		}
+	}
+	return slide
+}
+
+// currentPlacements returns where the images of the current slide are drawn
+func (m Model) currentPlacements() []images.Placement {
+	key := m.renderKey(m.Slides[m.Page])
+	if m.live(key.content) {
+		return m.liveSlides[key.live()].placements
+	}
+	return m.placements[key]
+}
+
+// liveViews returns the live slides in view: the current slide, or its
+// focused code block, and the preview of the next slide in the console
+func (m Model) liveViews() []liveView {
+	views := []liveView{{m: m, content: m.Slides[m.Page]}}
+	if block, ok := m.focusBlock(); ok {
+		views[0].content = block
+	}
+	if width := m.sidebarWidth(); m.Console && width > 0 && m.Page+1 < len(m.Slides) {
+		next := m.preview(width - 4)
+		views = append(views, liveView{m: next, content: next.Slides[next.Page]})
+	}
+	var live []liveView
+	for _, view := range views {
+		if m.live(view.content) {
+			live = append(live, view)
+		}
+	}
+	return live
+}
+
+// refresh renders the live slides in view in the background when they were
+// never rendered as they are displayed, when their page is entered or, for
+// slides showing the time, when the time changes. The slides are rendered
+// one refresh at a time so that slow commands don't pile up.
+func (m *Model) refresh() tea.Cmd {
+	if !m.ready || m.liveSlides == nil || m.refreshing {
+		return nil
+	}
+	var views []liveView
+	for _, view := range m.liveViews() {
+		key := view.m.renderKey(view.content)
+		slide, ok := m.liveSlides[key.live()]
+		if !ok || slide.key != key || m.entered || m.ticked && tmpl.HasDates(view.content) {
+			views = append(views, view)
+		}
+	}
+	m.entered, m.ticked = false, false
+	if len(views) == 0 {
+		return nil
+	}
+	m.refreshing = true
+	return func() tea.Msg {
+		refreshed := make(refreshedMsg, len(views))
+		for i, view := range views {
+			// The caches of the model aren't safe to share
+			r := view.m
+			r.rendered, r.placements, r.liveSlides = nil, nil, nil
+			r.filtered = map[string]string{}
+			slide, placements := r.render(view.content)
+			refreshed[i] = liveSlide{key: r.renderKey(view.content), slide: slide, placements: placements}
+		}
+		return refreshed
+	}
+}
+
+// render renders the markdown of a slide, returning where its images are
+// drawn
+func (m Model) render(content string) (string, []images.Placement) {
+	content = fragment.Reveal(content, m.fragment)
+	content = m.filter(content)
+	// Speaker notes are never shown to the audience
+	content = directive.Strip(content, "note")
+	content = tmpl.Dates(content, time.Now(), m.dateLayout)
+	if m.Templates {
+		content = tmpl.Render(content, tmpl.Data{
+			Author: m.Author,
+			Date:   m.Date,
+			Page:   m.Page + 1,
+			Total:  len(m.Slides),
+		}, m.AllowExec)
+	}
+	if m.ExtendedSyntax {
+		content = extended.Transform(content)
+	}
+	if m.Diagrams {
+		content = diagram.Transform(content)
+	}
+	theme := m.slideTheme(content)
+	var (
+		slide      string
+		placements []images.Placement
+		err        error
+	)
+	if parts := columns.Split(content); columns.Fit(parts, m.wrapWidth()) {
+		// Columns are rendered side by side, moving the images of each
+		// column to the right of the columns before it
+		width := m.wrapWidth() / len(parts)
+		rendered := make([]string, len(parts))
+		for i, part := range parts {
+			var columnPlacements []images.Placement
+			rendered[i], columnPlacements, err = m.renderMarkdown(part, theme, width)
+			if err != nil {
+				break
+			}
+			for _, p := range columnPlacements {
+				p.Col += i * width
+				placements = append(placements, p)
+			}
+			rendered[i] = lipgloss.NewStyle().Width(width).Render(rendered[i])
+		}
+		slide = lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+	} else {
+		// Columns which don't fit are stacked
+		slide, placements, err = m.renderMarkdown(strings.Join(parts, "\n\n"), theme, m.wrapWidth())
+	}
 	slide += m.VirtualText
 	if err != nil {
-		slide = fmt.Sprintf("Error: Could not render markdown! (%v)", err)
+		slide = m.errorPanel(err)
 	}
-	slide = styles.Slide.Render(slide)
-	return slide
+	slide = m.slideStyle().Render(slide)
+	if offset := m.alignOffset(slide); offset > 0 {
+		slide = lipgloss.NewStyle().PaddingLeft(offset).Render(slide)
+		for i := range placements {
+			placements[i].Col += offset
+		}
+	}
+	if m.Plain {
+		slide = reStyle.ReplaceAllString(slide, "")
+	}
+	return slide, placements
+}
+
+// renderMarkdown renders the markdown with the theme, wrapped at the width,
+// returning where the images of the rendered markdown are drawn
+func (m Model) renderMarkdown(content string, theme glamour.TermRendererOption, width int) (string, []images.Placement, error) {
+	var quotes []quote.Quote
+	if m.QuotePanels {
+		content, quotes = quote.Extract(content)
+	}
+	content, badges := badge.Extract(content)
+	content, pictures := images.Extract(content)
+	content, blocks := emphasis.Extract(content)
+	r, _ := glamour.NewTermRenderer(theme, glamour.WithWordWrap(width))
+	slide, err := r.Render(content)
+	slide = emphasis.Restore(slide, blocks, width, func(markdown string, width int) string {
+		r, _ := glamour.NewTermRenderer(theme, glamour.WithWordWrap(width))
+		block, _ := r.Render(markdown)
+		return block
+	})
+	slide = extended.Highlight(slide)
+	slide = quote.Restore(slide, quotes, width)
+	slide = badge.Restore(slide, badges)
+	slide, placements := images.Restore(slide, pictures, func(img images.Image) (int, int, bool) {
+		return m.imageSize(img, width)
+	})
+	return slide, placements, err
+}
+
+// errorPanel returns the panel shown in place of a slide which can't be
+// rendered, with the page of the slide and the error
+func (m Model) errorPanel(err error) string {
+	body := fmt.Sprintf("%s\n\nSlide %d could not be rendered:\n%v\n\nThe other slides can still be presented, J and K scroll this panel.",
+		styles.ErrorTitle.Render(renderError), m.Page+1, err)
+	width := m.wrapWidth() - styles.ErrorPanel.GetHorizontalBorderSize()
+	return styles.ErrorPanel.Width(max(1, width)).Render(body)
+}
+
+// wrapWidth returns the width slides are wrapped at, which is the width of the
+// viewport unless WordWrap is narrower. Padding wider than the default
+// padding narrows the width, and narrower padding widens it.
+func (m Model) wrapWidth() int {
+	width := m.viewport.Width - (m.slideStyle().GetHorizontalPadding() - styles.Slide.GetHorizontalPadding())
+	if m.WordWrap > 0 && m.WordWrap < width {
+		return m.WordWrap
+	}
+	return width
+}
+
+// slideStyle returns the style slides are rendered with, which has the
+// padding set in the metadata
+func (m Model) slideStyle() lipgloss.Style {
+	vertical, horizontal, ok := styles.ParsePadding(m.Padding)
+	if !ok {
+		return styles.Slide
+	}
+	return lipgloss.NewStyle().Padding(vertical, horizontal)
+}
+
+// alignOffset returns the number of columns the rendered slide is moved to
+// the right by to align it within the viewport
+func (m Model) alignOffset(slide string) int {
+	free := m.viewport.Width - lipgloss.Width(slide)
+	if free <= 0 {
+		return 0
+	}
+	switch m.Align {
+	case "center":
+		return free / 2
+	case "right":
+		return free
+	default:
+		return 0
+	}
+}
+
+// slideTheme returns the theme the slide sets with a theme directive, or the
+// theme of the deck if it doesn't set one which exists, with the code theme
+// of the deck applied to either
+func (m Model) slideTheme(content string) glamour.TermRendererOption {
+	if m.Plain {
+		return styles.SelectTheme("notty")
+	}
+	theme := ""
+	if name, ok := directive.Get(content, "theme"); ok {
+		if path := m.resolveTheme(name); styles.Exists(path) {
+			theme = path
+		}
+	}
+	if m.CodeTheme != "" {
+		if theme == "" {
+			theme = m.ThemePath()
+		}
+		return styles.WithCodeTheme(theme, m.CodeTheme)
+	}
+	if theme != "" {
+		return styles.SelectTheme(theme)
+	}
+	return m.Theme
+}
+
+// imageSize returns the size of the space the image is drawn in, scaled to
+// fit in the width and the height of the viewport, and whether the image can
+// be drawn
+func (m Model) imageSize(img images.Image, width int) (int, int, bool) {
+	if m.graphics == graphics.None {
+		return 0, 0, false
+	}
+	picture, err := graphics.Load(m.resolveAsset(img.Path))
+	if err != nil {
+		return 0, 0, false
+	}
+	bounds := picture.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return 0, 0, false
+	}
+
+	// Leave room for the margins of the slide, terminal cells are about
+	// twice as tall as they are wide
+	cols := width - 8
+	rows := cols * bounds.Dy() / bounds.Dx() / 2
+	if maxRows := m.viewport.Height - 4; rows > maxRows {
+		rows = maxRows
+		cols = rows * 2 * bounds.Dx() / bounds.Dy()
+	}
+	return cols, rows, cols > 0 && rows > 0
 }
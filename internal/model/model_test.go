@@ -0,0 +1,213 @@
+package model_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maaslalani/slides/internal/model"
+	"github.com/maaslalani/slides/internal/navigation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_relativePaths(t *testing.T) {
+	dir := t.TempDir()
+	deck := filepath.Join(dir, "deck.md")
+	content := "---\nbackground: bg.png\ncodeDir: demo\ncodeLog: run.log\n---\n# Slide\n"
+	if err := os.WriteFile(deck, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		fileName string
+	}{
+		{name: "File", fileName: deck},
+		{name: "Directory", fileName: dir},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model.Model{FileName: tt.fileName}
+			if err := m.Load(); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, filepath.Join(dir, "bg.png"), m.Background)
+			assert.Equal(t, filepath.Join(dir, "demo"), m.CodeDir)
+			assert.Equal(t, filepath.Join(dir, "run.log"), m.CodeLog)
+		})
+	}
+}
+
+func TestLoad_url(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("---\nbackground: bg.png\n---\n# Slide\n"))
+	}))
+	defer server.Close()
+	// Images are only drawn in terminals able to draw them
+	t.Setenv("KITTY_WINDOW_ID", "1")
+
+	m := model.Model{FileName: server.URL + "/talks/deck.md"}
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, server.URL+"/talks/bg.png", m.Background)
+	assert.Equal(t, "\nImages fetched from a URL are not drawn", m.VirtualText)
+}
+
+func TestUpdate_search(t *testing.T) {
+	deck := filepath.Join(t.TempDir(), "deck.md")
+	if err := os.WriteFile(deck, []byte("# One\n\n---\n\n# Two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := model.Model{FileName: deck, Search: navigation.NewSearch()}
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys which act on the slides are typed into the query instead
+	var tm tea.Model = m
+	for _, r := range "/jAse" {
+		tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m = tm.(model.Model)
+	assert.Equal(t, "jAse", m.Search.Query())
+	assert.Equal(t, 0, m.Page)
+}
+
+func TestLoad_dates(t *testing.T) {
+	deck := filepath.Join(t.TempDir(), "deck.md")
+	if err := os.WriteFile(deck, []byte("# {{time:15:04:05.000000}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := model.Model{FileName: deck}
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+	before := m.RenderCurrent()
+
+	// Reloading the unchanged slide shows the time it is rendered at
+	time.Sleep(time.Millisecond)
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, before, m.RenderCurrent())
+}
+
+func TestUpdate_execDisabled(t *testing.T) {
+	dir := t.TempDir()
+	ran := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	deck := filepath.Join(dir, "deck.md")
+	content := fmt.Sprintf("---\ntemplates: true\nslideFilter: touch %[1]s/filter\nonSlide: touch %[1]s/hook\n---\n"+
+		"# One\n\n{{ exec \"touch %[1]s/template\" }}\n\n```bash\ntouch %[1]s/code\n```\n\n---\n\n# Two\n", dir)
+	if err := os.WriteFile(deck, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := model.Model{FileName: deck}
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "\nonSlide is ignored unless allowExec is set", m.VirtualText)
+
+	var tm tea.Model = m
+	tm = update(tm, tea.WindowSizeMsg{Width: 200, Height: 40})
+	assert.Contains(t, tm.View(), "exec is disabled")
+
+	// Running the code blocks shows how to enable running them instead
+	tm = update(tm, tea.KeyMsg{Type: tea.KeyCtrlE})
+	assert.Contains(t, tm.View(), "running code is disabled, start slides with --exec or set allowExec: true")
+
+	tm = update(tm, tea.KeyMsg{Type: tea.KeyRight})
+	time.Sleep(100 * time.Millisecond)
+	for _, name := range []string{"filter", "hook", "template", "code"} {
+		assert.False(t, ran(name), name)
+	}
+}
+
+func TestUpdate_annotateClipboard(t *testing.T) {
+	deck := filepath.Join(t.TempDir(), "deck.md")
+	if err := os.WriteFile(deck, []byte("# One\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := model.Model{FileName: deck}
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+	// The clipboard is read in place of the file
+	m.FileName, m.Clipboard = "", true
+
+	var tm tea.Model = m
+	tm = update(tm, tea.WindowSizeMsg{Width: 120, Height: 30})
+	for _, r := range "Anote" {
+		tm = update(tm, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	tm = update(tm, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, tm.View(), "Error: can not add notes to slides read from the clipboard")
+}
+
+func TestUpdate_liveSlides(t *testing.T) {
+	deck := filepath.Join(t.TempDir(), "deck.md")
+	if err := os.WriteFile(deck, []byte("# {{time:15:04:05.000000}}\n\n---\n\n# Two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := model.Model{FileName: deck}
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var tm tea.Model = m
+	tm = update(tm, tea.WindowSizeMsg{Width: 80, Height: 24})
+	view := tm.View()
+	assert.Regexp(t, `\d\d:\d\d:\d\d\.\d{6}`, view)
+
+	// Viewing the slide shows it as it was rendered in the background
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, view, tm.View())
+
+	// Entering the slide again renders it again
+	tm = update(tm, tea.KeyMsg{Type: tea.KeyRight})
+	tm = update(tm, tea.KeyMsg{Type: tea.KeyLeft})
+	assert.NotEqual(t, view, tm.View())
+}
+
+// update updates the model with the message and with the messages of the
+// commands it returns, leaving out commands which take longer than a moment
+// such as ticks
+func update(m tea.Model, msg tea.Msg) tea.Model {
+	m, cmd := m.Update(msg)
+	for _, msg := range run(cmd) {
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+// run returns the messages of the command and of the commands it batches
+func run(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	select {
+	case msg := <-done:
+		batch := reflect.ValueOf(msg)
+		if batch.Kind() != reflect.Slice || batch.Type().Elem() != reflect.TypeOf(tea.Cmd(nil)) {
+			return []tea.Msg{msg}
+		}
+		var msgs []tea.Msg
+		for i := 0; i < batch.Len(); i++ {
+			msgs = append(msgs, run(batch.Index(i).Interface().(tea.Cmd))...)
+		}
+		return msgs
+	case <-time.After(100 * time.Millisecond):
+		return nil
+	}
+}
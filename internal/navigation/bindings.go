@@ -0,0 +1,109 @@
+package navigation
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultBindings are the actions which can be bound to other keys, along
+// with the keys they are bound to by default. Keys bound to an action act as
+// the first of its default keys.
+var DefaultBindings = map[string][]string{
+	"next":             {"right", " ", "l", "n", "pgdown", "down", "j"},
+	"previous":         {"left", "h", "p", "pgup", "up", "k"},
+	"first":            {"home"},
+	"last":             {"end"},
+	"search":           {"/"},
+	"finder":           {"ctrl+f"},
+	"nextMatch":        {"ctrl+n"},
+	"previousMatch":    {"ctrl+p"},
+	"run":              {"ctrl+e"},
+	"reveal":           {"e"},
+	"copy":             {"ctrl+y"},
+	"type":             {"ctrl+t"},
+	"focus":            {"f"},
+	"nextBlock":        {"]"},
+	"previousBlock":    {"["},
+	"annotate":         {"A"},
+	"lock":             {"L"},
+	"bookmark":         {"m"},
+	"nextBookmark":     {"'"},
+	"previousBookmark": {"\""},
+	"notes":            {"s"},
+	"scrollDown":       {"J"},
+	"scrollUp":         {"K"},
+	"agenda":           {"o"},
+	"overview":         {"tab"},
+	"chrome":           {"c"},
+	"theme":            {"t"},
+	"about":            {"i"},
+	"resume":           {"P"},
+	"quit":             {"q"},
+}
+
+// reserved are the keys which can't be bound to actions: the keys of slide
+// numbers, jumping to slides, dismissing panels and quitting
+var reserved = map[string]bool{
+	"0": true, "1": true, "2": true, "3": true, "4": true,
+	"5": true, "6": true, "7": true, "8": true, "9": true,
+	"g": true, "G": true, "enter": true, "esc": true, "ctrl+c": true,
+}
+
+// Bindings translates the keys of actions bound to other keys into the
+// default keys of the actions. The default keys of those actions translate
+// into no key at all, so that they no longer act.
+type Bindings map[string]string
+
+// ParseBindings binds the actions to the given keys instead of their default
+// keys, which are then free to be bound to other actions. Unknown actions and
+// keys bound to more than one action, including the default keys of actions
+// which aren't rebound, are errors.
+func ParseBindings(keys map[string][]string) (Bindings, error) {
+	actions := make([]string, 0, len(DefaultBindings))
+	for action := range DefaultBindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for action := range keys {
+		if _, ok := DefaultBindings[action]; !ok {
+			return nil, fmt.Errorf("unknown action %q in keys", action)
+		}
+	}
+
+	bindings := Bindings{}
+	for action := range keys {
+		for _, key := range DefaultBindings[action] {
+			bindings[key] = ""
+		}
+	}
+	owners := map[string]string{}
+	for _, action := range actions {
+		bound, custom := keys[action]
+		if !custom {
+			bound = DefaultBindings[action]
+		}
+		for _, key := range bound {
+			if custom && reserved[key] {
+				return nil, fmt.Errorf("key %q can't be bound to %s", key, action)
+			}
+			if owner, ok := owners[key]; ok && owner != action {
+				return nil, fmt.Errorf("key %q is bound to both %s and %s", key, owner, action)
+			}
+			owners[key] = action
+			if custom {
+				bindings[key] = DefaultBindings[action][0]
+			}
+		}
+	}
+	return bindings, nil
+}
+
+// Resolve returns the key the key press acts as, which is empty for the
+// default keys of rebound actions
+func (b Bindings) Resolve(keyPress string) string {
+	if key, ok := b[keyPress]; ok {
+		return key
+	}
+	return keyPress
+}
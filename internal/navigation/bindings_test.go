@@ -0,0 +1,70 @@
+package navigation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBindings(t *testing.T) {
+	bindings, err := ParseBindings(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Bindings{}, bindings)
+	assert.Equal(t, "j", bindings.Resolve("j"))
+
+	bindings, err = ParseBindings(map[string][]string{
+		"run":  {"ctrl+r"},
+		"next": {"x", "j"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ctrl+e", bindings.Resolve("ctrl+r"))
+	assert.Equal(t, "right", bindings.Resolve("x"))
+	assert.Equal(t, "right", bindings.Resolve("j"))
+	assert.Equal(t, "h", bindings.Resolve("h"))
+
+	// The default keys of rebound actions no longer act
+	assert.Equal(t, "", bindings.Resolve("ctrl+e"))
+	assert.Equal(t, "", bindings.Resolve("right"))
+
+	// Keys of rebound actions can be bound to other actions
+	bindings, err = ParseBindings(map[string][]string{
+		"next":     {"k"},
+		"previous": {"j"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "right", bindings.Resolve("k"))
+	assert.Equal(t, "left", bindings.Resolve("j"))
+	assert.Equal(t, "", bindings.Resolve("l"))
+
+	bindings, err = ParseBindings(map[string][]string{
+		"quit":  {"Q"},
+		"theme": {"q"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "q", bindings.Resolve("Q"))
+	assert.Equal(t, "t", bindings.Resolve("q"))
+	assert.Equal(t, "", bindings.Resolve("t"))
+}
+
+func TestParseBindings_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		keys map[string][]string
+		err  string
+	}{
+		{name: "Unknown action", keys: map[string][]string{"jump": {"x"}}, err: `unknown action "jump" in keys`},
+		{name: "Conflict with default", keys: map[string][]string{"next": {"h"}}, err: `key "h" is bound to both next and previous`},
+		{name: "Conflict with an action which isn't rebound", keys: map[string][]string{"theme": {"q"}}, err: `key "q" is bound to both quit and theme`},
+		{name: "Conflict with the about panel", keys: map[string][]string{"theme": {"i"}}, err: `key "i" is bound to both about and theme`},
+		{name: "Conflict with scrolling", keys: map[string][]string{"notes": {"J"}}, err: `key "J" is bound to both notes and scrollDown`},
+		{name: "Conflict with resuming", keys: map[string][]string{"next": {"P"}}, err: `key "P" is bound to both next and resume`},
+		{name: "Conflict", keys: map[string][]string{"run": {"x"}, "copy": {"x"}}, err: `key "x" is bound to both copy and run`},
+		{name: "Reserved", keys: map[string][]string{"quit": {"esc"}}, err: `key "esc" can't be bound to quit`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBindings(tt.keys)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
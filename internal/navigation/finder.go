@@ -0,0 +1,159 @@
+package navigation
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/maaslalani/slides/styles"
+)
+
+// Finder picks a slide to go to by its title, the titles are filtered
+// fuzzily as the query is typed
+type Finder struct {
+	Active bool
+	Input  textinput.Model
+	// titles are the titles of the slides, by page
+	titles []string
+	// selected is the position of the selected slide within the matches
+	selected int
+}
+
+func NewFinder() Finder {
+	ti := textinput.NewModel()
+	ti.Placeholder = "go to slide"
+	ti.Prompt = "> "
+	ti.PromptStyle = styles.Search
+	ti.TextStyle = styles.Search
+	return Finder{Input: ti}
+}
+
+// Begin opens the finder with the titles of the slides
+func (f *Finder) Begin(titles []string) {
+	f.Active = true
+	f.titles = titles
+	f.selected = 0
+	f.Input.SetValue("")
+	f.Input.Focus()
+}
+
+// Done closes the finder
+func (f *Finder) Done() {
+	f.Active = false
+	f.Input.Blur()
+}
+
+// Matches returns the pages of the slides whose titles match the query, best
+// matches first. Every slide matches an empty query, in order.
+func (f Finder) Matches() []int {
+	type match struct {
+		page, score int
+	}
+	var matches []match
+	for page, title := range f.titles {
+		if score, ok := Fuzzy(f.Input.Value(), title); ok {
+			matches = append(matches, match{page: page, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	pages := make([]int, len(matches))
+	for i, m := range matches {
+		pages[i] = m.page
+	}
+	return pages
+}
+
+// Move moves the selection through the matches by delta, keeping it within
+// the matches
+func (f *Finder) Move(delta int) {
+	n := len(f.Matches())
+	f.selected += delta
+	if f.selected >= n {
+		f.selected = n - 1
+	}
+	if f.selected < 0 {
+		f.selected = 0
+	}
+}
+
+// Reset selects the best match again, after the query changes
+func (f *Finder) Reset() {
+	f.selected = 0
+}
+
+// Selected returns the page of the selected slide, and whether any slide
+// matches the query
+func (f Finder) Selected() (int, bool) {
+	matches := f.Matches()
+	if len(matches) == 0 {
+		return 0, false
+	}
+	if f.selected >= len(matches) {
+		return matches[len(matches)-1], true
+	}
+	return matches[f.selected], true
+}
+
+// View renders the matches which fit in height lines, scrolled so that the
+// selected slide is visible
+func (f Finder) View(height int) string {
+	matches := f.Matches()
+	if len(matches) == 0 {
+		return styles.Unselected.Render("No slides match")
+	}
+	start := 0
+	if height > 0 && f.selected >= height {
+		start = f.selected - height + 1
+	}
+	var lines []string
+	for i := start; i < len(matches) && (height <= 0 || i < start+height); i++ {
+		page := matches[i]
+		line := strings.TrimSpace(f.titles[page])
+		if line == "" {
+			line = "(untitled)"
+		}
+		line = padPage(page+1, len(f.titles)) + "  " + line
+		if i == f.selected {
+			lines = append(lines, styles.Selected.Render("> "+line))
+		} else {
+			lines = append(lines, styles.Unselected.Render("  "+line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// padPage right aligns the page number to the width of the last page
+func padPage(page, total int) string {
+	s := strconv.Itoa(page)
+	return strings.Repeat(" ", len(strconv.Itoa(total))-len(s)) + s
+}
+
+// Fuzzy returns whether every character of the query appears in the text in
+// order, ignoring case, and a score which is higher for matches which are
+// consecutive or start words
+func Fuzzy(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	score, i := 0, 0
+	previous := -2
+	for j := 0; j < len(t) && i < len(q); j++ {
+		if t[j] != q[i] {
+			continue
+		}
+		switch {
+		case j == previous+1:
+			score += 3
+		case j == 0 || !unicode.IsLetter(t[j-1]) && !unicode.IsDigit(t[j-1]):
+			score += 2
+		default:
+			score++
+		}
+		previous = j
+		i++
+	}
+	return score, i == len(q)
+}
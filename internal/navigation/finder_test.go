@@ -0,0 +1,43 @@
+package navigation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzy(t *testing.T) {
+	_, ok := Fuzzy("bnch", "Benchmarks")
+	assert.True(t, ok)
+	_, ok = Fuzzy("xyz", "Benchmarks")
+	assert.False(t, ok)
+
+	consecutive, _ := Fuzzy("bench", "Benchmarks")
+	scattered, _ := Fuzzy("bench", "Be a nice change")
+	assert.Greater(t, consecutive, scattered)
+}
+
+func TestFinder(t *testing.T) {
+	f := NewFinder()
+	f.Begin([]string{"Introduction", "Benchmarks", "Be a nice change", "Questions"})
+
+	assert.Equal(t, []int{0, 1, 2, 3}, f.Matches())
+
+	f.Input.SetValue("bench")
+	assert.Equal(t, []int{1, 2}, f.Matches())
+	page, ok := f.Selected()
+	assert.True(t, ok)
+	assert.Equal(t, 1, page)
+
+	f.Move(1)
+	page, _ = f.Selected()
+	assert.Equal(t, 2, page)
+	f.Move(5)
+	page, _ = f.Selected()
+	assert.Equal(t, 2, page)
+
+	f.Input.SetValue("nothing matches this")
+	_, ok = f.Selected()
+	assert.False(t, ok)
+	assert.Contains(t, f.View(10), "No slides match")
+}
@@ -0,0 +1,42 @@
+package navigation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// KeyMap maps key presses to the slides they jump to
+type KeyMap map[string]int
+
+// ParseKeyMap parses key bindings written as "key -> slide", where slide is
+// the one based number of the slide to jump to. Invalid bindings are
+// ignored.
+func ParseKeyMap(bindings []string) KeyMap {
+	keys := KeyMap{}
+	for _, binding := range bindings {
+		parts := strings.SplitN(binding, "->", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		slide, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if key == "" || err != nil || slide < 1 {
+			continue
+		}
+		keys[key] = slide - 1
+	}
+	return keys
+}
+
+// Target returns the zero based page the key press jumps to, clamped to the
+// slides available
+func (k KeyMap) Target(keyPress string, totalSlides int) (int, bool) {
+	page, ok := k[keyPress]
+	if !ok {
+		return 0, false
+	}
+	if page > totalSlides-1 {
+		page = totalSlides - 1
+	}
+	return page, true
+}
@@ -0,0 +1,27 @@
+package navigation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyMap(t *testing.T) {
+	got := ParseKeyMap([]string{"a -> 5", "b->2", "c -> x", "-> 3", "d -> 0", "nothing"})
+	assert.Equal(t, KeyMap{"a": 4, "b": 1}, got)
+}
+
+func TestKeyMapTarget(t *testing.T) {
+	keys := KeyMap{"a": 4, "b": 20}
+
+	page, ok := keys.Target("a", 10)
+	assert.True(t, ok)
+	assert.Equal(t, 4, page)
+
+	page, ok = keys.Target("b", 10)
+	assert.True(t, ok)
+	assert.Equal(t, 9, page)
+
+	_, ok = keys.Target("c", 10)
+	assert.False(t, ok)
+}
@@ -42,6 +42,16 @@ func Navigate(state State, keyPress string) State {
 				TotalSlides: state.TotalSlides,
 			}
 		}
+	case "home":
+		return State{
+			Page:        0,
+			TotalSlides: state.TotalSlides,
+		}
+	case "end":
+		return State{
+			Page:        state.TotalSlides - 1,
+			TotalSlides: state.TotalSlides,
+		}
 	case "G":
 		targetSlide := state.TotalSlides - 1
 		if bufferIsNumeric(state.Buffer) {
@@ -52,12 +62,23 @@ func Navigate(state State, keyPress string) State {
 			Page:        targetSlide,
 			TotalSlides: state.TotalSlides,
 		}
-	case " ", "right", "l", "enter", "n", "pgdown":
+	case "enter":
+		// A number followed by enter jumps to that slide, like G
+		if bufferIsNumeric(state.Buffer) {
+			return State{
+				Page:        navigateSlide(state.Buffer, state.TotalSlides),
+				TotalSlides: state.TotalSlides,
+			}
+		}
+	}
+
+	switch Direction(keyPress) {
+	case Next:
 		return State{
 			Page:        navigateNext(state),
 			TotalSlides: state.TotalSlides,
 		}
-	case "left", "h", "p", "pgup":
+	case Previous:
 		return State{
 			Page:        navigatePrevious(state),
 			TotalSlides: state.TotalSlides,
@@ -70,6 +91,26 @@ func Navigate(state State, keyPress string) State {
 	}
 }
 
+// Directions in which a key press moves through the slides
+const (
+	None     = 0
+	Next     = 1
+	Previous = -1
+)
+
+// Direction returns whether the key press moves to the next or the previous
+// slide
+func Direction(keyPress string) int {
+	switch keyPress {
+	case " ", "right", "l", "enter", "n", "pgdown", "down", "j":
+		return Next
+	case "left", "h", "p", "pgup", "up", "k":
+		return Previous
+	default:
+		return None
+	}
+}
+
 func bufferIsNumeric(buffer string) bool {
 	_, err := strconv.Atoi(buffer)
 	return err == nil
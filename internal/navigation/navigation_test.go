@@ -25,6 +25,7 @@ func TestNavigation(t *testing.T) {
 		{keys: "3G", target: 2},
 		{keys: "11G", target: 10},
 		{keys: "101G", target: 10},
+		{keys: "jjk", target: 1},
 	}
 
 	for _, tt := range tests {
@@ -44,3 +45,58 @@ func TestNavigation(t *testing.T) {
 		})
 	}
 }
+
+func TestNavigation_enter(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []string
+		target int
+	}{
+		{name: "Number and enter", keys: []string{"7", "enter"}, target: 6},
+		{name: "Out of range", keys: []string{"9", "9", "enter"}, target: 10},
+		{name: "Enter alone", keys: []string{"enter"}, target: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := State{Page: 2, TotalSlides: 11}
+			for _, key := range tt.keys {
+				state = Navigate(state, key)
+			}
+			assert.Equal(t, State{Page: tt.target, TotalSlides: 11}, state)
+		})
+	}
+}
+
+func TestNavigation_homeEnd(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []string
+		target int
+	}{
+		{name: "Home", keys: []string{"home"}, target: 0},
+		{name: "End", keys: []string{"end"}, target: 10},
+		{name: "End then home", keys: []string{"end", "home"}, target: 0},
+		{name: "Buffer is cleared", keys: []string{"3", "end", "k"}, target: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := State{Page: 4, TotalSlides: 11}
+			for _, key := range tt.keys {
+				state = Navigate(state, key)
+			}
+			assert.Equal(t, State{Page: tt.target, TotalSlides: 11}, state)
+		})
+	}
+}
+
+func TestDirection(t *testing.T) {
+	assert.Equal(t, Next, Direction("l"))
+	assert.Equal(t, Next, Direction(" "))
+	assert.Equal(t, Previous, Direction("h"))
+	assert.Equal(t, Previous, Direction("pgup"))
+	assert.Equal(t, Next, Direction("down"))
+	assert.Equal(t, Previous, Direction("k"))
+	assert.Equal(t, None, Direction("G"))
+}
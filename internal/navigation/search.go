@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/maaslalani/slides/styles"
 )
 
@@ -22,6 +23,9 @@ type Search struct {
 	Active bool
 	// Query stores the current "search term"
 	SearchTextInput textinput.Model
+	// Match is the pattern of the last search, its matches are highlighted
+	// on the slides until the search is cleared
+	Match *regexp.Regexp
 }
 
 func NewSearch() Search {
@@ -33,6 +37,22 @@ func NewSearch() Search {
 	return Search{SearchTextInput: ti}
 }
 
+// Customize changes the prompt, placeholder and color of the search bar,
+// empty values keep the defaults
+func (s *Search) Customize(prompt, placeholder, color string) {
+	if prompt != "" {
+		s.SearchTextInput.Prompt = prompt
+	}
+	if placeholder != "" {
+		s.SearchTextInput.Placeholder = placeholder
+	}
+	if color != "" {
+		style := styles.Search.Copy().Faint(false).Foreground(lipgloss.Color(color))
+		s.SearchTextInput.PromptStyle = style
+		s.SearchTextInput.TextStyle = style
+	}
+}
+
 func (s *Search) Query() string {
 	return s.SearchTextInput.Value()
 }
@@ -49,44 +69,157 @@ func (s *Search) Done() {
 	s.Active = false
 }
 
+// Clear removes the highlighting of the last search
+func (s *Search) Clear() {
+	s.Match = nil
+}
+
 // Begin a new search (deletes old buffer)
 func (s *Search) Begin() {
 	s.Active = true
 	s.SetQuery("")
 }
 
-// Execute search
-func (s *Search) Execute(m Model) {
+// Execute search, going to the next slide matching the query. It returns a
+// message to show when the search wrapped around or found nothing.
+func (s *Search) Execute(m Model) string {
+	return s.execute(m, 1)
+}
+
+// ExecuteBackward searches backwards, going to the previous slide matching
+// the query
+func (s *Search) ExecuteBackward(m Model) string {
+	return s.execute(m, -1)
+}
+
+func (s *Search) execute(m Model, direction int) string {
 	defer s.Done()
 	expr := s.Query()
 	if expr == "" {
-		return
+		return ""
 	}
 	if strings.HasSuffix(expr, "/i") {
 		expr = "(?i)" + expr[:len(expr)-2]
 	}
 	pattern, err := regexp.Compile(expr)
 	if err != nil {
-		return
+		return "Invalid search: " + err.Error()
+	}
+	s.Match = pattern
+	// search every other slide in the direction, wrapping around at the
+	// first and last slide
+	total := len(m.Pages())
+	current := m.CurrentPage()
+	for n := 1; n < total; n++ {
+		i := current + direction*n
+		page := (i%total + total) % total
+		if len(pattern.FindAllStringSubmatch(m.Pages()[page], 1)) == 0 {
+			continue
+		}
+		m.SetPage(page)
+		if i != page {
+			return "Search wrapped"
+		}
+		return ""
+	}
+	return "No other slide matches " + s.Query()
+}
+
+// Escape sequences turning reverse video on and off
+const (
+	reverse   = "\x1b[7m"
+	noReverse = "\x1b[27m"
+)
+
+// Highlight shows every match of the last search in the rendered slide in
+// reverse video. The pattern is matched against the text of each line as it
+// is displayed, without its escape sequences.
+func (s *Search) Highlight(rendered string) string {
+	if s.Match == nil {
+		return rendered
 	}
-	check := func(i int) bool {
-		content := m.Pages()[i]
-		if len(pattern.FindAllStringSubmatch(content, 1)) != 0 {
-			m.SetPage(i)
-			return true
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		lines[i] = highlightLine(line, s.Match)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func highlightLine(line string, pattern *regexp.Regexp) string {
+	var plain strings.Builder
+	// offsets holds the position in the line of every byte of the plain text
+	var offsets []int
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			i = escapeEnd(line, i)
+			continue
 		}
-		return false
+		plain.WriteByte(line[i])
+		offsets = append(offsets, i)
+		i++
 	}
-	// search from next slide to end
-	for i := m.CurrentPage() + 1; i < len(m.Pages()); i++ {
-		if check(i) {
-			return
+
+	matches := pattern.FindAllStringIndex(plain.String(), -1)
+	if len(matches) == 0 {
+		return line
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		if match[0] == match[1] {
+			continue
 		}
+		start, end := offsets[match[0]], offsets[match[1]-1]+1
+		b.WriteString(line[last:start])
+		b.WriteString(reverse)
+		// The styles of the slide may reset the reverse video within the
+		// match, so it is turned back on after every escape sequence
+		for i := start; i < end; {
+			if line[i] == '\x1b' {
+				next := escapeEnd(line, i)
+				b.WriteString(line[i:next])
+				b.WriteString(reverse)
+				i = next
+				continue
+			}
+			b.WriteByte(line[i])
+			i++
+		}
+		b.WriteString(noReverse)
+		last = end
 	}
-	// search from first slide to previous
-	for i := 0; i < m.CurrentPage(); i++ {
-		if check(i) {
-			return
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// escapeEnd returns the position right after the escape sequence starting at
+// the given position of the line
+func escapeEnd(line string, start int) int {
+	i := start + 1
+	if i >= len(line) {
+		return i
+	}
+	switch line[i] {
+	case '[':
+		// Control sequences end with a byte between @ and ~
+		for i++; i < len(line); i++ {
+			if line[i] >= 0x40 && line[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		// Operating system commands, such as hyperlinks, end with BEL or ST
+		for i++; i < len(line); i++ {
+			if line[i] == '\a' {
+				return i + 1
+			}
+			if line[i] == '\x1b' && i+1 < len(line) && line[i+1] == '\\' {
+				return i + 2
+			}
 		}
+	default:
+		return i + 1
 	}
+	return len(line)
 }
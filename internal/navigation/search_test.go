@@ -2,6 +2,8 @@ package navigation
 
 import (
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 type mockModel struct {
@@ -69,3 +71,113 @@ func TestSearch(t *testing.T) {
 	}
 
 }
+
+func TestSearch_backward(t *testing.T) {
+	m := &mockModel{
+		slides: []string{"match", "other", "match", "other", "match"},
+		page:   2,
+	}
+
+	s := &Search{}
+	s.SetQuery("match")
+	for _, expected := range []int{0, 4, 2, 0} {
+		s.ExecuteBackward(m)
+		if m.CurrentPage() != expected {
+			t.Errorf("expected page %d, got %d", expected, m.CurrentPage())
+		}
+	}
+}
+
+func TestSearch_feedback(t *testing.T) {
+	m := &mockModel{
+		slides: []string{"match", "other", "match"},
+		page:   0,
+	}
+
+	s := &Search{}
+	s.SetQuery("match")
+	for _, expected := range []string{"", "Search wrapped"} {
+		if got := s.Execute(m); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+
+	s.SetQuery("missing")
+	if got := s.Execute(m); got != "No other slide matches missing" {
+		t.Errorf("expected no matches, got %q", got)
+	}
+}
+
+func TestSearch_highlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		rendered string
+		want     string
+	}{
+		{
+			name:     "No search",
+			rendered: "hello world",
+			want:     "hello world",
+		},
+		{
+			name:     "Every occurrence",
+			query:    "o",
+			rendered: "hello world",
+			want:     "hell\x1b[7mo\x1b[27m w\x1b[7mo\x1b[27mrld",
+		},
+		{
+			name:     "Ignore case",
+			query:    "WORLD/i",
+			rendered: "hello world\nWorld",
+			want:     "hello \x1b[7mworld\x1b[27m\n\x1b[7mWorld\x1b[27m",
+		},
+		{
+			name:     "Across escape sequences",
+			query:    "lo w",
+			rendered: "\x1b[1mhello\x1b[0m \x1b[1mworld\x1b[0m",
+			want:     "\x1b[1mhel\x1b[7mlo\x1b[0m\x1b[7m \x1b[1m\x1b[7mw\x1b[27morld\x1b[0m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockModel{slides: []string{"", "hello world"}}
+			s := &Search{}
+			if tt.query != "" {
+				s.SetQuery(tt.query)
+				s.Execute(m)
+			}
+			if got := s.Highlight(tt.rendered); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	s := &Search{}
+	s.SetQuery("o")
+	s.Execute(&mockModel{slides: []string{"", "o"}})
+	s.Clear()
+	if got := s.Highlight("hello"); got != "hello" {
+		t.Errorf("expected no highlight after clearing, got %q", got)
+	}
+}
+
+func TestCustomize(t *testing.T) {
+	s := NewSearch()
+	s.Customize("", "", "")
+	if s.SearchTextInput.Prompt != "/" || s.SearchTextInput.Placeholder != "search" {
+		t.Errorf("expected default prompt and placeholder, got %q and %q", s.SearchTextInput.Prompt, s.SearchTextInput.Placeholder)
+	}
+
+	s.Customize("find: ", "term", "#ff0000")
+	if s.SearchTextInput.Prompt != "find: " {
+		t.Errorf("expected prompt %q, got %q", "find: ", s.SearchTextInput.Prompt)
+	}
+	if s.SearchTextInput.Placeholder != "term" {
+		t.Errorf("expected placeholder %q, got %q", "term", s.SearchTextInput.Placeholder)
+	}
+	if s.SearchTextInput.PromptStyle.GetForeground() != lipgloss.Color("#ff0000") {
+		t.Errorf("expected prompt color to be customized")
+	}
+}
@@ -0,0 +1,86 @@
+package navigation
+
+import "strings"
+
+// Tree arranges the slides in two dimensions, each section is a column of
+// slides. Moving horizontally jumps between the sections and moving
+// vertically moves through the slides of the current section.
+type Tree struct {
+	// Starts are the pages on which each section starts, in order
+	Starts      []int
+	TotalSlides int
+}
+
+// Position returns the zero based section containing the page and the
+// position of the page within that section
+func (t Tree) Position(page int) (int, int) {
+	section := 0
+	for i, start := range t.Starts {
+		if start <= page {
+			section = i
+		}
+	}
+	if len(t.Starts) == 0 {
+		return 0, page
+	}
+	return section, page - t.Starts[section]
+}
+
+// end returns the last page of the section
+func (t Tree) end(section int) int {
+	if section+1 < len(t.Starts) {
+		return t.Starts[section+1] - 1
+	}
+	return t.TotalSlides - 1
+}
+
+// Navigate returns the page the key press moves to and whether the key press
+// is a tree movement
+func (t Tree) Navigate(page int, keyPress string) (int, bool) {
+	if len(t.Starts) == 0 {
+		return page, false
+	}
+	section, _ := t.Position(page)
+	switch keyPress {
+	case "right", "l":
+		if section+1 < len(t.Starts) {
+			return t.Starts[section+1], true
+		}
+		return page, true
+	case "left", "h":
+		if section > 0 {
+			return t.Starts[section-1], true
+		}
+		return t.Starts[0], true
+	case "down", "j":
+		if page < t.end(section) {
+			return page + 1, true
+		}
+		return page, true
+	case "up", "k":
+		if page > t.Starts[section] {
+			return page - 1, true
+		}
+		return page, true
+	}
+	return page, false
+}
+
+// VerticalDelimiter separates the vertical slides of a section
+const VerticalDelimiter = "\n--\n"
+
+// Vertical splits every slide into the vertical slides of its section,
+// returning the slides in order and the tree of the sections. The tree has
+// no sections if none of the slides are split.
+func Vertical(slides []string) ([]string, Tree) {
+	var split []string
+	var starts []int
+	for _, slide := range slides {
+		starts = append(starts, len(split))
+		split = append(split, strings.Split(slide, VerticalDelimiter)...)
+	}
+	if len(split) == len(slides) {
+		return slides, Tree{TotalSlides: len(slides)}
+	}
+	return split, Tree{Starts: starts, TotalSlides: len(split)}
+}
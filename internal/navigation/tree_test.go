@@ -0,0 +1,60 @@
+package navigation_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/navigation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	// Sections of slides: [0 1 2] [3] [4 5]
+	tree := navigation.Tree{Starts: []int{0, 3, 4}, TotalSlides: 6}
+
+	tests := []struct {
+		page     int
+		keyPress string
+		want     int
+		ok       bool
+	}{
+		{page: 1, keyPress: "l", want: 3, ok: true},
+		{page: 4, keyPress: "right", want: 4, ok: true},
+		{page: 5, keyPress: "h", want: 3, ok: true},
+		{page: 1, keyPress: "left", want: 0, ok: true},
+		{page: 0, keyPress: "j", want: 1, ok: true},
+		{page: 2, keyPress: "down", want: 2, ok: true},
+		{page: 5, keyPress: "k", want: 4, ok: true},
+		{page: 3, keyPress: "up", want: 3, ok: true},
+		{page: 2, keyPress: "n", want: 2, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyPress, func(t *testing.T) {
+			got, ok := tree.Navigate(tt.page, tt.keyPress)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestTree_Position(t *testing.T) {
+	tree := navigation.Tree{Starts: []int{0, 3, 4}, TotalSlides: 6}
+
+	section, slide := tree.Position(5)
+	assert.Equal(t, 2, section)
+	assert.Equal(t, 1, slide)
+
+	section, slide = tree.Position(2)
+	assert.Equal(t, 0, section)
+	assert.Equal(t, 2, slide)
+}
+
+func TestVertical(t *testing.T) {
+	slides, tree := navigation.Vertical([]string{"# One\n--\nDetail\n--\nMore", "# Two", "# Three\n--\nDetail"})
+	assert.Equal(t, []string{"# One", "Detail", "More", "# Two", "# Three", "Detail"}, slides)
+	assert.Equal(t, navigation.Tree{Starts: []int{0, 3, 4}, TotalSlides: 6}, tree)
+
+	slides, tree = navigation.Vertical([]string{"# One", "# Two"})
+	assert.Equal(t, []string{"# One", "# Two"}, slides)
+	assert.Empty(t, tree.Starts)
+}
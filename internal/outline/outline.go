@@ -0,0 +1,191 @@
+// Package outline implements extracting the structure of a presentation from
+// the headings of its slides
+package outline
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/maaslalani/slides/internal/directive"
+)
+
+var reHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+// Heading returns the level and text of the first heading of the slide, the
+// level is zero if the slide has no heading
+func Heading(slide string) (int, string) {
+	if all := headings(slide); len(all) > 0 {
+		return all[0].level, all[0].text
+	}
+	return 0, ""
+}
+
+type heading struct {
+	level int
+	text  string
+}
+
+// headings returns every heading of the slide outside of code blocks
+func headings(slide string) []heading {
+	var (
+		rv    []heading
+		fence bool
+	)
+	for _, line := range strings.Split(slide, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = !fence
+			continue
+		}
+		if fence {
+			continue
+		}
+		if match := reHeading.FindStringSubmatch(trimmed); match != nil {
+			rv = append(rv, heading{level: len(match[1]), text: match[2]})
+		}
+	}
+	return rv
+}
+
+// TOCMarker is the content of a slide which is replaced by the table of
+// contents of the deck
+const TOCMarker = "[toc]"
+
+// IsTOC returns whether the slide is replaced by the table of contents
+func IsTOC(slide string) bool {
+	return strings.EqualFold(strings.TrimSpace(slide), TOCMarker)
+}
+
+// TOC returns a slide listing the top-level and second-level headings of the
+// slides, along with the number of the slide each is on
+func TOC(slides []string) string {
+	var b strings.Builder
+	b.WriteString("## Contents\n")
+	for i, slide := range slides {
+		for _, h := range headings(slide) {
+			switch h.level {
+			case 1:
+				fmt.Fprintf(&b, "\n- %s (%d)", h.text, i+1)
+			case 2:
+				fmt.Fprintf(&b, "\n  - %s (%d)", h.text, i+1)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Title returns the text of the first heading of the slide, or its first
+// line of text if it has no heading
+func Title(slide string) string {
+	if _, text := Heading(slide); text != "" {
+		return text
+	}
+	for _, line := range strings.Split(directive.Strip(slide, "note"), "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// Section is a group of slides starting with a top-level heading
+type Section struct {
+	Title string
+	// Start is the page of the first slide in the section
+	Start int
+	// Planned is the sum of the planned durations of the section's slides
+	Planned time.Duration
+}
+
+// Sections groups the slides into sections, a new section starts on every
+// slide with a top-level heading. Slides before the first top-level heading
+// form a section of their own.
+func Sections(slides []string) []Section {
+	var sections []Section
+	for i, slide := range slides {
+		if level, text := Heading(slide); level == 1 || i == 0 {
+			if text == "" {
+				text = Title(slide)
+			}
+			sections = append(sections, Section{Title: text, Start: i})
+		}
+		sections[len(sections)-1].Planned += Duration(slide)
+	}
+	return sections
+}
+
+// Current returns the index of the section containing the page
+func Current(sections []Section, page int) int {
+	current := 0
+	for i, section := range sections {
+		if section.Start <= page {
+			current = i
+		}
+	}
+	return current
+}
+
+// IsSectionStart returns whether the page begins a new section
+func IsSectionStart(sections []Section, page int) bool {
+	for _, section := range sections {
+		if section.Start == page {
+			return true
+		}
+	}
+	return false
+}
+
+// Duration returns the planned duration of a slide, which is set with a
+// <!-- duration: 2m --> directive
+func Duration(slide string) time.Duration {
+	value, ok := directive.Get(slide, "duration")
+	if !ok {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// Agenda renders the list of sections with their planned durations, marking
+// the current section. When the sections have planned durations the elapsed
+// time is compared to the time planned up to the end of the current section.
+func Agenda(sections []Section, page int, elapsed time.Duration) string {
+	current := Current(sections, page)
+
+	var (
+		b       strings.Builder
+		planned time.Duration
+		total   time.Duration
+	)
+	b.WriteString("Agenda\n\n")
+	for i, section := range sections {
+		marker := "  "
+		if i == current {
+			marker = "▶ "
+		}
+		b.WriteString(marker + section.Title)
+		if section.Planned > 0 {
+			b.WriteString(" (" + section.Planned.String() + ")")
+		}
+		b.WriteString("\n")
+
+		total += section.Planned
+		if i <= current {
+			planned += section.Planned
+		}
+	}
+
+	if total > 0 {
+		elapsed = elapsed.Round(time.Second)
+		fmt.Fprintf(&b, "\n%s / %s", elapsed, planned)
+		if elapsed > planned {
+			fmt.Fprintf(&b, " (+%s)", elapsed-planned)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package outline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maaslalani/slides/internal/outline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeading(t *testing.T) {
+	tests := []struct {
+		slide string
+		level int
+		text  string
+	}{
+		{slide: "# Title\ntext", level: 1, text: "Title"},
+		{slide: "text\n## Subtitle ##", level: 2, text: "Subtitle"},
+		{slide: "~~~bash\n# comment\n~~~\n### Heading", level: 3, text: "Heading"},
+		{slide: "no heading", level: 0, text: ""},
+	}
+	for _, tt := range tests {
+		level, text := outline.Heading(tt.slide)
+		assert.Equal(t, tt.level, level, tt.slide)
+		assert.Equal(t, tt.text, text, tt.slide)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	assert.Equal(t, "Title", outline.Title("text\n# Title"))
+	assert.Equal(t, "first line", outline.Title("<!-- note: hidden -->\n\nfirst line\nsecond"))
+	assert.Equal(t, "", outline.Title(""))
+}
+
+var slides = []string{
+	"Welcome\n<!-- duration: 1m -->",
+	"# Intro\n<!-- duration: 2m -->",
+	"## Details\n<!-- duration: 30s -->",
+	"# Q&A\n<!-- duration: 5m -->",
+}
+
+func TestTOC(t *testing.T) {
+	assert.True(t, outline.IsTOC("\n[TOC]\n"))
+	assert.False(t, outline.IsTOC("[toc] and more"))
+
+	deck := []string{
+		"[toc]",
+		"# Intro\n## Why\n### Not listed",
+		"```bash\n# comment\n```\n## Details",
+	}
+	want := "## Contents\n\n- Intro (2)\n  - Why (2)\n  - Details (3)"
+	assert.Equal(t, want, outline.TOC(deck))
+}
+
+func TestSections(t *testing.T) {
+	want := []outline.Section{
+		{Title: "Welcome", Start: 0, Planned: time.Minute},
+		{Title: "Intro", Start: 1, Planned: 2*time.Minute + 30*time.Second},
+		{Title: "Q&A", Start: 3, Planned: 5 * time.Minute},
+	}
+	sections := outline.Sections(slides)
+	assert.Equal(t, want, sections)
+
+	assert.Equal(t, 0, outline.Current(sections, 0))
+	assert.Equal(t, 1, outline.Current(sections, 2))
+	assert.Equal(t, 2, outline.Current(sections, 3))
+
+	assert.True(t, outline.IsSectionStart(sections, 1))
+	assert.False(t, outline.IsSectionStart(sections, 2))
+}
+
+func TestDuration(t *testing.T) {
+	assert.Equal(t, 90*time.Second, outline.Duration("<!-- duration: 1m30s -->"))
+	assert.Equal(t, time.Duration(0), outline.Duration("<!-- duration: soon -->"))
+	assert.Equal(t, time.Duration(0), outline.Duration("# No duration"))
+}
+
+func TestAgenda(t *testing.T) {
+	sections := outline.Sections(slides)
+	want := "Agenda\n\n  Welcome (1m0s)\n▶ Intro (2m30s)\n  Q&A (5m0s)\n\n4m0s / 3m30s (+30s)"
+	assert.Equal(t, want, outline.Agenda(sections, 2, 4*time.Minute))
+
+	want = "Agenda\n\n▶ One\n  Two\n"
+	assert.Equal(t, want, outline.Agenda(outline.Sections([]string{"# One", "# Two"}), 0, time.Minute))
+}
@@ -0,0 +1,108 @@
+// Package overview implements the slide sorter, a grid of miniature previews
+// of every slide in the presentation
+package overview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maaslalani/slides/styles"
+)
+
+const (
+	// cellWidth is the width of the text inside of each cell of the grid
+	cellWidth = 20
+	// The border of each cell takes up a column and a line on every side
+	outerWidth  = cellWidth + 2
+	outerHeight = 4
+)
+
+// Columns returns the number of cells which fit on a row of the given width
+func Columns(width int) int {
+	if width < outerWidth {
+		return 1
+	}
+	return width / outerWidth
+}
+
+// Move returns the cell selected after moving the selection with the key,
+// the selection doesn't move if the key isn't one of the arrow keys or their
+// vim equivalents.
+func Move(selected, total, columns int, key string) int {
+	switch key {
+	case "left", "h":
+		selected--
+	case "right", "l":
+		selected++
+	case "up", "k":
+		if selected-columns >= 0 {
+			selected -= columns
+		}
+	case "down", "j":
+		if selected+columns < total {
+			selected += columns
+		}
+	case "home", "g":
+		selected = 0
+	case "end", "G":
+		selected = total - 1
+	}
+	if selected < 0 {
+		return 0
+	}
+	if selected >= total {
+		return total - 1
+	}
+	return selected
+}
+
+// Render renders the titles of the slides in a grid which fills the width,
+// marking the current slide and highlighting the selected one. Only the rows
+// around the selected slide are shown when the grid is taller than height.
+func Render(titles []string, current, selected, width, height int) string {
+	columns := Columns(width)
+	var rows []string
+	for start := 0; start < len(titles); start += columns {
+		var cells []string
+		for i := start; i < start+columns && i < len(titles); i++ {
+			cells = append(cells, cell(titles[i], i, i == current, i == selected))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+
+	visible := height / outerHeight
+	if visible < 1 {
+		visible = 1
+	}
+	if len(rows) > visible {
+		first := selected/columns - visible + 1
+		if first < 0 {
+			first = 0
+		}
+		rows = rows[first : first+visible]
+	}
+	return strings.Join(rows, "\n")
+}
+
+func cell(title string, page int, current, selected bool) string {
+	number := fmt.Sprintf("%d", page+1)
+	if current {
+		number += " ●"
+	}
+	style := styles.OverviewCell
+	if selected {
+		style = styles.OverviewSelected
+	}
+	return style.Render(truncate(number, cellWidth) + "\n" + truncate(title, cellWidth))
+}
+
+// truncate shortens text to the width, ending it with an ellipsis if any of
+// it was cut off
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,58 @@
+package overview_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maaslalani/slides/internal/overview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumns(t *testing.T) {
+	assert.Equal(t, 1, overview.Columns(10))
+	assert.Equal(t, 1, overview.Columns(22))
+	assert.Equal(t, 3, overview.Columns(80))
+}
+
+func TestMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		selected int
+		key      string
+		want     int
+	}{
+		{name: "Right", selected: 0, key: "right", want: 1},
+		{name: "Left at start", selected: 0, key: "h", want: 0},
+		{name: "Right wraps to next row", selected: 2, key: "l", want: 3},
+		{name: "Down", selected: 1, key: "down", want: 4},
+		{name: "Down past last row", selected: 6, key: "j", want: 6},
+		{name: "Up past first row", selected: 1, key: "k", want: 1},
+		{name: "Up", selected: 5, key: "up", want: 2},
+		{name: "End", selected: 0, key: "G", want: 7},
+		{name: "Other key", selected: 3, key: "x", want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, overview.Move(tt.selected, 8, 3, tt.key))
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	titles := []string{"Welcome", "Agenda", "A very long title which is cut off", "Thanks"}
+	got := overview.Render(titles, 1, 3, 80, 24)
+
+	lines := strings.Split(got, "\n")
+	// Two rows of cells, each with a border above and below
+	assert.Len(t, lines, 8)
+	assert.Contains(t, got, "Welcome")
+	assert.Contains(t, got, "2 ●")
+	assert.Contains(t, got, "A very long title w…")
+	assert.Contains(t, got, "Thanks")
+
+	// Only the row with the selected slide is shown when there's not enough
+	// room for every row
+	got = overview.Render(titles, 1, 3, 80, 4)
+	assert.Contains(t, got, "Thanks")
+	assert.NotContains(t, got, "Welcome")
+}
@@ -0,0 +1,81 @@
+// Package pace implements estimating how long slides take to present from
+// the number of words on them
+package pace
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultWordsPerMinute is the speaking rate slides are estimated with by
+// default
+const DefaultWordsPerMinute = 130
+
+var (
+	reComment = regexp.MustCompile(`(?s)<!--(.*?)-->`)
+	reImage   = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	reLink    = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+)
+
+// Words returns the number of words of the slide which are spoken, leaving
+// out code blocks, images, link targets and comments other than speaker
+// notes
+func Words(slide string) int {
+	slide = reComment.ReplaceAllStringFunc(slide, func(comment string) string {
+		body := strings.TrimSpace(reComment.FindStringSubmatch(comment)[1])
+		if strings.HasPrefix(body, "note:") {
+			return " " + strings.TrimPrefix(body, "note:") + " "
+		}
+		return " "
+	})
+	slide = reImage.ReplaceAllString(slide, " ")
+	slide = reLink.ReplaceAllString(slide, "$1")
+
+	var words int
+	var fence string
+	for _, line := range strings.Split(slide, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")) {
+			fence = trimmed[:3]
+			continue
+		}
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if strings.IndexFunc(field, isWordRune) >= 0 {
+				words++
+			}
+		}
+	}
+	return words
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Estimate returns how long it takes to say the words at the given number of
+// words per minute, or at DefaultWordsPerMinute if it isn't positive
+func Estimate(words, wordsPerMinute int) time.Duration {
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+	return time.Duration(words) * time.Minute / time.Duration(wordsPerMinute)
+}
+
+// Format formats the estimate to the nearest minute, such as "~18 min"
+func Format(d time.Duration) string {
+	minutes := math.Round(d.Minutes())
+	if minutes < 1 {
+		return "<1 min"
+	}
+	return fmt.Sprintf("~%d min", int(minutes))
+}
@@ -0,0 +1,40 @@
+package pace_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maaslalani/slides/internal/pace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name  string
+		slide string
+		want  int
+	}{
+		{name: "Empty", slide: "", want: 0},
+		{name: "Markdown", slide: "# Getting started\n\n* Install **slides**\n* Write a [deck](https://example.com)", want: 7},
+		{name: "Code blocks", slide: "# Code\n\n```go\nfmt.Println(\"hello world\")\n```\n\nThat's it", want: 3},
+		{name: "Images", slide: "![a diagram of the system](./diagram.png)\nThe system", want: 2},
+		{name: "Notes", slide: "# Demo\n<!-- note: show the output -->\n<!-- duration: 2m -->", want: 4},
+		{name: "Punctuation", slide: "a --- b | c", want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pace.Words(tt.slide))
+		})
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	assert.Equal(t, 2*time.Minute, pace.Estimate(200, 100))
+	assert.Equal(t, time.Minute, pace.Estimate(pace.DefaultWordsPerMinute, 0))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "<1 min", pace.Format(20*time.Second))
+	assert.Equal(t, "~1 min", pace.Format(40*time.Second))
+	assert.Equal(t, "~18 min", pace.Format(18*time.Minute+10*time.Second))
+}
@@ -0,0 +1,56 @@
+// Package placeholder implements keeping parts of a slide away from glamour.
+// The parts are replaced with placeholders made of private use characters,
+// which pass through glamour untouched, and the lines of the placeholders are
+// replaced with the parts once the slide is rendered.
+package placeholder
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Kind is a kind of placeholder, delimited by characters of its own
+type Kind struct {
+	// Start and End delimit the placeholders
+	Start, End string
+	re         *regexp.Regexp
+}
+
+// The kinds of placeholders, each of them takes the next two private use
+// characters so that no two kinds are mistaken for one another
+var (
+	// Highlight delimits highlighted text within a line, rather than
+	// standing in for a part of the slide
+	Highlight = newKind('\uE000')
+	Quote     = newKind('\uE002')
+	Badge     = newKind('\uE004')
+	Image     = newKind('\uE006')
+	CodeBlock = newKind('\uE008')
+)
+
+func newKind(start rune) Kind {
+	k := Kind{Start: string(start), End: string(start + 1)}
+	k.re = regexp.MustCompile(k.Start + `(\d+)` + k.End)
+	return k
+}
+
+// Mark returns the placeholder of the nth part
+func (k Kind) Mark(n int) string {
+	return k.Start + strconv.Itoa(n) + k.End
+}
+
+// Find returns which part the placeholder on the rendered line stands in for
+// and the column the placeholder starts at, or false if there is none
+func (k Kind) Find(line string) (n, col int, ok bool) {
+	loc := k.re.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(line[loc[2]:loc[3]])
+	if err != nil {
+		return 0, 0, false
+	}
+	return n, lipgloss.Width(line[:loc[0]]), true
+}
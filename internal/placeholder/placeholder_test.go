@@ -0,0 +1,22 @@
+package placeholder_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/placeholder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	line := "  \x1b[1m" + placeholder.Quote.Mark(12) + "\x1b[0m  "
+	n, col, ok := placeholder.Quote.Find(line)
+	assert.True(t, ok)
+	assert.Equal(t, 12, n)
+	assert.Equal(t, 2, col)
+
+	// The placeholders of other kinds aren't found
+	_, _, ok = placeholder.Image.Find(line)
+	assert.False(t, ok)
+	_, _, ok = placeholder.Quote.Find("plain text")
+	assert.False(t, ok)
+}
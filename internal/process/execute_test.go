@@ -1,6 +1,10 @@
 package process
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestExecute(t *testing.T) {
 	tt := []struct {
@@ -21,6 +25,13 @@ func TestExecute(t *testing.T) {
 			},
 			want: "Replace",
 		},
+		{
+			block: Block{
+				Command: "ls process.go",
+				Dir:     ".",
+			},
+			want: "process.go\n",
+		},
 	}
 
 	for _, tc := range tt {
@@ -36,3 +47,31 @@ func TestExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	if got := Filter("# hello", "tr a-z A-Z", "."); got != "# HELLO" {
+		t.Fatalf("Invalid filter, want # HELLO, got %s", got)
+	}
+	if got := Filter("# hello", "false", "."); got != "# hello" {
+		t.Fatalf("Failed filter should keep content, want # hello, got %s", got)
+	}
+}
+
+func TestHook(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	dir := t.TempDir()
+	if err := Hook("touch slide-{page}-of-{total}", dir, 2, 5, "Intro"); err != nil {
+		t.Fatalf("Hook failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "slide-2-of-5")); err != nil {
+		t.Fatalf("Hook didn't run the command: %v", err)
+	}
+	if err := Hook("sh -c false", dir, 1, 1, ""); err == nil {
+		t.Fatal("Failed hook should return an error")
+	}
+}
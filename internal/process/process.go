@@ -1,13 +1,20 @@
 package process
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// hookTimeout stops hooks which run for longer, so that they don't pile up
+// while changing slides
+const hookTimeout = 10 * time.Second
+
 // Block represents a pre-processable block which looks like the following: It
 // is delimited by ~~~ and contains a command to be run along with the input to
 // be passed, the entire block should be replaced with its command output
@@ -20,6 +27,9 @@ type Block struct {
 	Input   string
 	Output  string
 	Raw     string
+	// Dir is the working directory of the command, the current directory is
+	// used if it is empty
+	Dir string
 }
 
 func (b Block) String() string {
@@ -50,6 +60,7 @@ func Parse(markdown string) []Block {
 func (b *Block) Execute() {
 	c := strings.Split(b.Command, " ")
 	cmd := exec.Command(c[0], c[1:]...)
+	cmd.Dir = b.Dir
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return
@@ -68,9 +79,22 @@ func (b *Block) Execute() {
 	b.Output = string(out)
 }
 
+// Filter pipes the content through the command, which is run in dir, and
+// returns its output. The content is returned untouched if the command fails
+// or has no output.
+func Filter(content, command, dir string) string {
+	b := Block{Command: command, Input: content, Dir: dir}
+	b.Execute()
+	if b.Output == "" {
+		return content
+	}
+	return b.Output
+}
+
 // Pre processes the markdown content by executing the commands necessary and
-// returns the new processed content
-func Pre(content string) string {
+// returns the new processed content. Commands are run in dir, so that
+// relative paths resolve against the directory of the slides.
+func Pre(content, dir string) string {
 	blocks := Parse(content)
 
 	if len(blocks) <= 0 {
@@ -79,6 +103,7 @@ func Pre(content string) string {
 
 	for _, block := range blocks {
 		// TODO: Use goroutines, if possible
+		block.Dir = dir
 		block.Execute()
 
 		// If multiple blocks have the same Raw value The will _likely_ have the
@@ -88,3 +113,30 @@ func Pre(content string) string {
 	}
 	return content
 }
+
+// Hook runs the command of a slide change in dir, with {page}, {total} and
+// {title} in its arguments replaced with the slide shown. The output of the
+// command is included in the error if it fails.
+func Hook(command, dir string, page, total int, title string) error {
+	replacer := strings.NewReplacer("{page}", strconv.Itoa(page), "{total}", strconv.Itoa(total), "{title}", title)
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil
+	}
+	for i, arg := range args {
+		args[i] = replacer.Replace(arg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if output := strings.TrimSpace(string(out)); output != "" {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package progress implements indicators showing how far into the
+// presentation the current slide is
+package progress
+
+import "strings"
+
+const (
+	// Dots is the progress style rendering one dot per slide
+	Dots = "dots"
+)
+
+const (
+	dotFilled = "●"
+	dotEmpty  = "○"
+	ellipsis  = "…"
+	// tick replaces the space before the first slide of a section
+	tick = "│"
+)
+
+// RenderDots returns a row of dots, one for each slide, with the dot of the
+// current page filled in. A tick mark is drawn before the dot of each page in
+// sections, which are the pages on which sections start.
+//
+// If there are too many slides to fit within width the row collapses to a
+// window of dots around the current page, with an ellipsis marking the
+// omitted slides on either side.
+func RenderDots(page, total, width int, sections []int) string {
+	if total <= 0 || width <= 0 {
+		return ""
+	}
+
+	// Each dot is followed by a space except the last one
+	if total*2-1 <= width {
+		return join(dots(page, 0, total), 0, sections)
+	}
+
+	// Leave room for an ellipsis and a space on each side of the window
+	visible := (width - 4 + 1) / 2
+	if visible < 1 {
+		return dotFilled
+	}
+
+	start := page - visible/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + visible
+	if end > total {
+		end = total
+		start = end - visible
+	}
+
+	row := join(dots(page, start, end), start, sections)
+	if start > 0 {
+		row = ellipsis + " " + row
+	}
+	if end < total {
+		row = row + " " + ellipsis
+	}
+	return row
+}
+
+func dots(page, start, end int) []string {
+	var rv []string
+	for i := start; i < end; i++ {
+		if i == page {
+			rv = append(rv, dotFilled)
+		} else {
+			rv = append(rv, dotEmpty)
+		}
+	}
+	return rv
+}
+
+// join joins the dots of the pages from start, separating the dots with a tick
+// mark where a section starts and a space everywhere else
+func join(dots []string, start int, sections []int) string {
+	ticks := map[int]bool{}
+	for _, page := range sections {
+		ticks[page] = true
+	}
+
+	var b strings.Builder
+	for i, dot := range dots {
+		if i > 0 {
+			if ticks[start+i] {
+				b.WriteString(tick)
+			} else {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(dot)
+	}
+	return b.String()
+}
+
+const (
+	// Bar is the paging style rendering a bar which fills up as the
+	// presentation progresses
+	Bar = "bar"
+	// MinBarWidth is the narrowest bar worth rendering, narrower bars should
+	// fall back to numeric paging
+	MinBarWidth = 10
+)
+
+const (
+	barFilled = "━"
+	barEmpty  = "─"
+)
+
+// RenderBar returns a bar of the given width, filled in proportionally to the
+// pages up to and including the current page. The filled and empty parts of
+// the bar are returned separately so they can be styled. A tick mark is drawn
+// where each of the sections starts.
+func RenderBar(page, total, width int, sections []int) (string, string) {
+	if total <= 0 || width <= 0 {
+		return "", ""
+	}
+
+	filled := (page + 1) * width / total
+	if filled > width {
+		filled = width
+	}
+
+	ticks := map[int]bool{}
+	for _, start := range sections {
+		if start > 0 && start < total {
+			ticks[start*width/total] = true
+		}
+	}
+
+	var done, rest strings.Builder
+	for i := 0; i < width; i++ {
+		b, char := &rest, barEmpty
+		if i < filled {
+			b, char = &done, barFilled
+		}
+		if ticks[i] {
+			char = tick
+		}
+		b.WriteString(char)
+	}
+	return done.String(), rest.String()
+}
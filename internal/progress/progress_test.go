@@ -0,0 +1,62 @@
+package progress_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderDots(t *testing.T) {
+	tests := []struct {
+		name  string
+		page  int
+		total int
+		width int
+		want  string
+	}{
+		{name: "No slides", page: 0, total: 0, width: 80, want: ""},
+		{name: "First slide", page: 0, total: 3, width: 80, want: "● ○ ○"},
+		{name: "Last slide", page: 2, total: 3, width: 80, want: "○ ○ ●"},
+		{name: "Exact fit", page: 1, total: 3, width: 5, want: "○ ● ○"},
+		{name: "Compact at start", page: 0, total: 20, width: 11, want: "● ○ ○ ○ …"},
+		{name: "Compact in middle", page: 10, total: 20, width: 11, want: "… ○ ○ ● ○ …"},
+		{name: "Compact at end", page: 19, total: 20, width: 11, want: "… ○ ○ ○ ●"},
+		{name: "Too narrow", page: 5, total: 20, width: 2, want: "●"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, progress.RenderDots(tt.page, tt.total, tt.width, nil))
+		})
+	}
+}
+
+func TestRenderDots_sections(t *testing.T) {
+	assert.Equal(t, "○ ●│○ ○│○", progress.RenderDots(1, 5, 80, []int{0, 2, 4}))
+	assert.Equal(t, "… ○ ○│● ○ …", progress.RenderDots(10, 20, 11, []int{0, 10}))
+}
+
+func TestRenderBar(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     int
+		total    int
+		width    int
+		sections []int
+		filled   string
+		empty    string
+	}{
+		{name: "No slides", page: 0, total: 0, width: 10},
+		{name: "First slide", page: 0, total: 5, width: 10, filled: "━━", empty: "────────"},
+		{name: "Last slide", page: 4, total: 5, width: 10, filled: "━━━━━━━━━━"},
+		{name: "Uneven", page: 0, total: 3, width: 10, filled: "━━━", empty: "───────"},
+		{name: "Sections", page: 1, total: 5, width: 10, sections: []int{0, 3}, filled: "━━━━", empty: "──│───"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filled, empty := progress.RenderBar(tt.page, tt.total, tt.width, tt.sections)
+			assert.Equal(t, tt.filled, filled)
+			assert.Equal(t, tt.empty, empty)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package quote implements rendering blockquotes as styled panels, with an
+// optional attribution taken from a trailing "— Author" line
+package quote
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maaslalani/slides/internal/placeholder"
+	"github.com/maaslalani/slides/styles"
+)
+
+var reAttribution = regexp.MustCompile(`^(?:—|--|―)\s*(.+)$`)
+
+// Quote is a blockquote extracted from a slide
+type Quote struct {
+	// Paragraphs are the paragraphs of the quote, with their lines joined
+	Paragraphs  []string
+	Attribution string
+}
+
+// Extract replaces the blockquotes of the markdown, outside of code blocks,
+// with placeholders and returns the quotes which were replaced
+func Extract(markdown string) (string, []Quote) {
+	var (
+		quotes []Quote
+		out    []string
+		block  []string
+		fence  bool
+	)
+
+	flush := func() {
+		if block == nil {
+			return
+		}
+		out = append(out, "", placeholder.Quote.Mark(len(quotes)), "")
+		quotes = append(quotes, parse(block))
+		block = nil
+	}
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = !fence
+		}
+		if !fence && strings.HasPrefix(trimmed, ">") {
+			block = append(block, strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+			continue
+		}
+		flush()
+		out = append(out, line)
+	}
+	flush()
+
+	return strings.Join(out, "\n"), quotes
+}
+
+// parse splits the lines of a blockquote into paragraphs and its attribution
+func parse(lines []string) Quote {
+	var (
+		q         Quote
+		paragraph []string
+	)
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if n := len(lines); n > 0 {
+		if match := reAttribution.FindStringSubmatch(lines[n-1]); match != nil {
+			q.Attribution = match[1]
+			lines = lines[:n-1]
+		}
+	}
+	for _, line := range lines {
+		if line == "" {
+			if paragraph != nil {
+				q.Paragraphs = append(q.Paragraphs, strings.Join(paragraph, " "))
+				paragraph = nil
+			}
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+	if paragraph != nil {
+		q.Paragraphs = append(q.Paragraphs, strings.Join(paragraph, " "))
+	}
+	return q
+}
+
+// Render renders the quote as a panel which is at most width columns wide
+func Render(q Quote, width int) string {
+	body := styles.Quote.Copy().Width(max(width-2, 1)).Render(strings.Join(q.Paragraphs, "\n\n"))
+	if q.Attribution == "" {
+		return body
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, body, "", styles.QuoteAttribution.Render("— "+q.Attribution))
+}
+
+// Restore replaces the lines of the rendered slide containing placeholders
+// with the panels of their quotes, keeping the indentation of the line
+func Restore(rendered string, quotes []Quote, width int) string {
+	lines := strings.Split(rendered, "\n")
+	var out []string
+	for _, line := range lines {
+		i, col, ok := placeholder.Quote.Find(line)
+		if !ok || i >= len(quotes) {
+			out = append(out, line)
+			continue
+		}
+		indent := strings.Repeat(" ", col)
+		for _, l := range strings.Split(Render(quotes[i], width-len(indent)), "\n") {
+			out = append(out, indent+l)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,43 @@
+package quote_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maaslalani/slides/internal/quote"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	markdown := "# Quote\n> Simplicity is\n> prerequisite\n>\n> for reliability\n> — Edsger Dijkstra\n\n```\n> not a quote\n```"
+
+	got, quotes := quote.Extract(markdown)
+
+	assert.Equal(t, []quote.Quote{{
+		Paragraphs:  []string{"Simplicity is prerequisite", "for reliability"},
+		Attribution: "Edsger Dijkstra",
+	}}, quotes)
+	assert.Equal(t, "# Quote\n\n\uE0020\uE003\n\n\n```\n> not a quote\n```", got)
+}
+
+func TestExtract_noAttribution(t *testing.T) {
+	_, quotes := quote.Extract("> first\n\ntext\n\n> second -- not attributed")
+
+	assert.Equal(t, []quote.Quote{
+		{Paragraphs: []string{"first"}},
+		{Paragraphs: []string{"second -- not attributed"}},
+	}, quotes)
+}
+
+func TestRestore(t *testing.T) {
+	quotes := []quote.Quote{{Paragraphs: []string{"Hello"}, Attribution: "Gopher"}}
+
+	got := quote.Restore("before\n  \uE0020\uE003  \nafter", quotes, 40)
+	lines := strings.Split(got, "\n")
+
+	assert.Equal(t, "before", lines[0])
+	assert.Contains(t, lines[1], "Hello")
+	assert.True(t, strings.HasPrefix(lines[1], "  "))
+	assert.Contains(t, got, "— Gopher")
+	assert.Equal(t, "after", lines[len(lines)-1])
+}
@@ -0,0 +1,76 @@
+// Package rehearsal records how long is spent on each slide while
+// practicing a presentation
+package rehearsal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Recorder tracks the time spent on each slide
+type Recorder struct {
+	page      int
+	start     time.Time
+	stopped   bool
+	durations map[int]time.Duration
+}
+
+// New creates a recorder which starts timing the slide on the given page at
+// the given time
+func New(page int, now time.Time) *Recorder {
+	return &Recorder{
+		page:      page,
+		start:     now,
+		durations: map[int]time.Duration{},
+	}
+}
+
+// Record stops timing the current slide and starts timing the given page
+func (r *Recorder) Record(page int, now time.Time) {
+	if r.stopped {
+		return
+	}
+	r.durations[r.page] += now.Sub(r.start)
+	r.page = page
+	r.start = now
+}
+
+// Stop stops timing the current slide, once stopped no more time is
+// recorded
+func (r *Recorder) Stop(now time.Time) {
+	r.Record(r.page, now)
+	r.stopped = true
+}
+
+// Total returns the total time recorded across all slides
+func (r *Recorder) Total() time.Duration {
+	var total time.Duration
+	for _, d := range r.durations {
+		total += d
+	}
+	return total
+}
+
+// Duration returns the time recorded for the given page
+func (r *Recorder) Duration(page int) time.Duration {
+	return r.durations[page]
+}
+
+// Summary returns a human readable breakdown of the time spent on every
+// visited slide
+func (r *Recorder) Summary() string {
+	var pages []int
+	for page := range r.durations {
+		pages = append(pages, page)
+	}
+	sort.Ints(pages)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Total: %s\n", r.Total().Round(time.Second))
+	for _, page := range pages {
+		fmt.Fprintf(&b, "Slide %d: %s\n", page+1, r.durations[page].Round(time.Second))
+	}
+	return b.String()
+}
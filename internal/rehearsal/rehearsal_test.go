@@ -0,0 +1,37 @@
+package rehearsal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maaslalani/slides/internal/rehearsal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := rehearsal.New(0, start)
+
+	r.Record(1, start.Add(10*time.Second))
+	r.Record(0, start.Add(15*time.Second))
+	r.Record(2, start.Add(20*time.Second))
+	r.Stop(start.Add(50 * time.Second))
+
+	// Time after stopping is not recorded
+	r.Record(1, start.Add(90*time.Second))
+
+	assert.Equal(t, 15*time.Second, r.Duration(0))
+	assert.Equal(t, 5*time.Second, r.Duration(1))
+	assert.Equal(t, 30*time.Second, r.Duration(2))
+	assert.Equal(t, 50*time.Second, r.Total())
+	assert.Equal(t, "Total: 50s\nSlide 1: 15s\nSlide 2: 5s\nSlide 3: 30s\n", r.Summary())
+}
+
+func TestRecorder_startPage(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := rehearsal.New(4, start)
+	r.Record(5, start.Add(10*time.Second))
+
+	assert.Equal(t, time.Duration(0), r.Duration(0))
+	assert.Equal(t, 10*time.Second, r.Duration(4))
+}
@@ -0,0 +1,68 @@
+// Package resume remembers the page each deck was last left on, so that
+// presenting it again can resume from there
+package resume
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File returns the file the pages of every deck are stored in
+func File() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "slides", "pages.json"), nil
+}
+
+// Load returns the page the deck was last left on, and whether it was
+// stored in the file
+func Load(file, deck string) (int, bool) {
+	pages := read(file)
+	page, ok := pages[key(deck)]
+	return page, ok
+}
+
+// Save stores the page the deck was left on in the file, along with the
+// pages of the other decks
+func Save(file, deck string, page int) error {
+	pages := read(file)
+	pages[key(deck)] = page
+	data, err := json.MarshalIndent(pages, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o644)
+}
+
+// read returns the pages stored in the file, a missing or invalid file has
+// no pages
+func read(file string) map[string]int {
+	pages := map[string]int{}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return pages
+	}
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return map[string]int{}
+	}
+	return pages
+}
+
+// key identifies the deck by its absolute path, decks fetched from a URL are
+// identified by the URL
+func key(deck string) string {
+	if strings.HasPrefix(deck, "http://") || strings.HasPrefix(deck, "https://") {
+		return deck
+	}
+	if abs, err := filepath.Abs(deck); err == nil {
+		return abs
+	}
+	return deck
+}
@@ -0,0 +1,45 @@
+package resume_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maaslalani/slides/internal/resume"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "slides", "pages.json")
+
+	_, ok := resume.Load(file, "talk.md")
+	assert.False(t, ok)
+
+	assert.NoError(t, resume.Save(file, "talk.md", 6))
+	assert.NoError(t, resume.Save(file, "https://example.com/other.md", 2))
+
+	page, ok := resume.Load(file, "talk.md")
+	assert.True(t, ok)
+	assert.Equal(t, 6, page)
+
+	// Decks are identified by their absolute path
+	abs, err := filepath.Abs("talk.md")
+	assert.NoError(t, err)
+	page, _ = resume.Load(file, abs)
+	assert.Equal(t, 6, page)
+
+	page, _ = resume.Load(file, "https://example.com/other.md")
+	assert.Equal(t, 2, page)
+}
+
+func TestLoad_invalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "pages.json")
+	assert.NoError(t, os.WriteFile(file, []byte("not json"), 0o644))
+
+	_, ok := resume.Load(file, "talk.md")
+	assert.False(t, ok)
+	assert.NoError(t, resume.Save(file, "talk.md", 1))
+	page, ok := resume.Load(file, "talk.md")
+	assert.True(t, ok)
+	assert.Equal(t, 1, page)
+}
@@ -0,0 +1,32 @@
+// Package timer implements displaying the time elapsed since the
+// presentation started
+package timer
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock formats the duration as mm:ss, or h:mm:ss once it reaches an hour
+func Clock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Truncate(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// Render returns the elapsed time, followed by the target duration when
+// there is one, along with whether the elapsed time ran past the target
+func Render(elapsed, target time.Duration) (string, bool) {
+	if target <= 0 {
+		return Clock(elapsed), false
+	}
+	return Clock(elapsed) + " / " + Clock(target), elapsed > target
+}
@@ -0,0 +1,37 @@
+package timer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maaslalani/slides/internal/timer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 0, want: "00:00"},
+		{d: 59*time.Second + 900*time.Millisecond, want: "00:59"},
+		{d: 12*time.Minute + 5*time.Second, want: "12:05"},
+		{d: time.Hour + 2*time.Minute + 3*time.Second, want: "1:02:03"},
+		{d: -time.Second, want: "00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, timer.Clock(tt.d))
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	got, over := timer.Render(90*time.Second, 0)
+	assert.Equal(t, "01:30", got)
+	assert.False(t, over)
+
+	got, over = timer.Render(21*time.Minute, 20*time.Minute)
+	assert.Equal(t, "21:00 / 20:00", got)
+	assert.True(t, over)
+}
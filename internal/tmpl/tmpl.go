@@ -0,0 +1,133 @@
+// Package tmpl implements evaluating Go templates in slides so they can
+// display live data
+package tmpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Data is the information about the presentation available to templates
+type Data struct {
+	Author string
+	Date   string
+	Page   int
+	Total  int
+}
+
+var (
+	ErrExecDisabled = errors.New("exec is disabled, start slides with --exec or set allowExec: true to enable it")
+)
+
+// ExecTimeout stops commands run by the exec function which run for longer,
+// since slides are rendered while they run
+var ExecTimeout = 5 * time.Second
+
+// Render evaluates the slide as a template with the given data. The exec
+// function, which runs a command and returns its output, is only available
+// if allowExec is true.
+//
+// Template errors do not prevent the slide from rendering, instead the
+// original slide is returned with the error appended to it.
+func Render(slide string, data Data, allowExec bool) string {
+	if !strings.Contains(slide, "{{") {
+		return slide
+	}
+
+	t, err := template.New("slide").Funcs(funcs(allowExec)).Parse(slide)
+	if err != nil {
+		return withError(slide, err)
+	}
+
+	var b strings.Builder
+	err = t.Execute(&b, data)
+	if err != nil {
+		return withError(slide, err)
+	}
+	return b.String()
+}
+
+// variable matches placeholders of variables, such as {{ .company }}
+var variable = regexp.MustCompile(`{{\s*\.(\w+)\s*}}`)
+
+// Substitute replaces the placeholders of the variables in the slide with
+// their values. Placeholders of undefined variables are left as they are, so
+// that they are displayed literally or evaluated as templates.
+func Substitute(slide string, vars map[string]string) string {
+	if len(vars) == 0 {
+		return slide
+	}
+	return variable.ReplaceAllStringFunc(slide, func(placeholder string) string {
+		name := variable.FindStringSubmatch(placeholder)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// timeLayout is the layout of {{time}} tokens without a layout
+const timeLayout = "15:04"
+
+// token matches the date and time tokens, such as {{date:2006-01-02}}
+var token = regexp.MustCompile(`{{\s*(date|time)(?::([^}]*))?\s*}}`)
+
+// Dates replaces the {{date}} and {{time}} tokens in the slide with the
+// given time. Tokens may give a Go layout after a colon, such as
+// {{date:Jan 2, 2006}}, otherwise the date is formatted with dateLayout and
+// the time as hours and minutes.
+func Dates(slide string, now time.Time, dateLayout string) string {
+	if !strings.Contains(slide, "{{") {
+		return slide
+	}
+	return token.ReplaceAllStringFunc(slide, func(t string) string {
+		match := token.FindStringSubmatch(t)
+		layout := strings.TrimSpace(match[2])
+		if layout == "" {
+			layout = dateLayout
+			if match[1] == "time" {
+				layout = timeLayout
+			}
+		}
+		return now.Format(layout)
+	})
+}
+
+// HasDates returns whether the slide has any {{date}} or {{time}} tokens
+func HasDates(slide string) bool {
+	return token.MatchString(slide)
+}
+
+func funcs(allowExec bool) template.FuncMap {
+	return template.FuncMap{
+		"exec": func(command string) (string, error) {
+			if !allowExec {
+				return "", ErrExecDisabled
+			}
+			c := strings.Fields(command)
+			if len(c) == 0 {
+				return "", nil
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), ExecTimeout)
+			defer cancel()
+			out, err := exec.CommandContext(ctx, c[0], c[1:]...).Output()
+			if ctx.Err() == context.DeadlineExceeded {
+				return "", fmt.Errorf("timed out after %s", ExecTimeout)
+			}
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimSuffix(string(out), "\n"), nil
+		},
+	}
+}
+
+func withError(slide string, err error) string {
+	return slide + "\n\n> Template error: " + err.Error()
+}
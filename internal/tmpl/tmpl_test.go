@@ -0,0 +1,100 @@
+package tmpl_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maaslalani/slides/internal/tmpl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	data := tmpl.Data{Author: "Gopher", Date: "2022-01-02", Page: 2, Total: 5}
+
+	tests := []struct {
+		name      string
+		slide     string
+		allowExec bool
+		want      string
+	}{
+		{name: "No template", slide: "# Slide", want: "# Slide"},
+		{name: "Data", slide: "{{ .Author }} on {{ .Date }} ({{ .Page }}/{{ .Total }})", want: "Gopher on 2022-01-02 (2/5)"},
+		{name: "Exec", slide: `{{ exec "echo hello" }}`, allowExec: true, want: "hello"},
+		{
+			name:  "Exec disabled",
+			slide: `{{ exec "echo hello" }}`,
+			want:  "{{ exec \"echo hello\" }}\n\n> Template error: template: slide:1:3: executing \"slide\" at <exec \"echo hello\">: error calling exec: " + tmpl.ErrExecDisabled.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.allowExec && testing.Short() {
+				t.SkipNow()
+			}
+			assert.Equal(t, tt.want, tmpl.Render(tt.slide, data, tt.allowExec))
+		})
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	vars := map[string]string{"company": "Acme", "year": "2022"}
+
+	tests := []struct {
+		name  string
+		slide string
+		want  string
+	}{
+		{name: "No placeholders", slide: "# Slide", want: "# Slide"},
+		{name: "Variables", slide: "# {{.company}} in {{ .year }}", want: "# Acme in 2022"},
+		{name: "Undefined", slide: "{{ .client }} and {{ .Author }}", want: "{{ .client }} and {{ .Author }}"},
+		{name: "Not a variable", slide: "{{ exec \"date\" }}", want: "{{ exec \"date\" }}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tmpl.Substitute(tt.slide, vars))
+		})
+	}
+}
+
+func TestDates(t *testing.T) {
+	now := time.Date(2022, time.March, 4, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		slide string
+		want  string
+	}{
+		{name: "No tokens", slide: "# Slide", want: "# Slide"},
+		{name: "Date", slide: "Generated on {{date}}", want: "Generated on 2022-03-04"},
+		{name: "Date layout", slide: "{{date:Jan 2, 2006}}", want: "Mar 4, 2022"},
+		{name: "Time", slide: "{{ time }} or {{time:3:04pm}}", want: "15:30 or 3:30pm"},
+		{name: "Template", slide: "{{ .Date }}", want: "{{ .Date }}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tmpl.Dates(tt.slide, now, "2006-01-02"))
+		})
+	}
+}
+
+func TestRender_execTimeout(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	defer func(timeout time.Duration) { tmpl.ExecTimeout = timeout }(tmpl.ExecTimeout)
+	tmpl.ExecTimeout = 10 * time.Millisecond
+
+	got := tmpl.Render(`{{ exec "sleep 5" }}`, tmpl.Data{}, true)
+	assert.True(t, strings.HasSuffix(got, "error calling exec: timed out after 10ms"), got)
+}
+
+func TestHasDates(t *testing.T) {
+	assert.True(t, tmpl.HasDates("Generated on {{ date:Jan 2 }}"))
+	assert.False(t, tmpl.HasDates("{{ .Date }}"))
+}
+
+func TestRender_parseError(t *testing.T) {
+	got := tmpl.Render("{{ .Author ", tmpl.Data{}, false)
+	assert.True(t, strings.HasPrefix(got, "{{ .Author \n\n> Template error: "))
+}
@@ -0,0 +1,180 @@
+// Package transition implements animating the change from one rendered
+// slide to another
+package transition
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Transitions between slides
+const (
+	// None changes slides instantly
+	None = "none"
+	// Wipe reveals the new slide from the top down
+	Wipe = "wipe"
+	// Slide pushes the old slide out to the left as the new slide slides in
+	// from the right
+	Slide = "slide"
+	// Fade fades the old slide out and the new slide in
+	Fade = "fade"
+)
+
+// Scopes of the slide changes which are animated
+const (
+	// All animates every change of slide
+	All = "all"
+	// Sections animates changes of slide which cross into another section
+	// and changes slides instantly within a section
+	Sections = "sections"
+)
+
+const (
+	// Frames is the number of frames of a transition
+	Frames = 8
+	// Interval is the time between the frames of a transition
+	Interval = 25 * time.Millisecond
+)
+
+// Frame returns the frame of the transition between two rendered slides,
+// where frame counts from 1 up to Frames. Unknown transitions change slides
+// instantly.
+func Frame(transition, from, to string, frame int) string {
+	if frame >= Frames {
+		return to
+	}
+	switch transition {
+	case Wipe:
+		return wipe(from, to, float64(frame)/Frames)
+	case Slide:
+		return slide(from, to, float64(frame)/Frames)
+	case Fade:
+		return fade(from, to, float64(frame)/Frames)
+	default:
+		return to
+	}
+}
+
+// wipe shows the top lines of the new slide above the remaining lines of the
+// old one
+func wipe(from, to string, progress float64) string {
+	fromLines := strings.Split(from, "\n")
+	toLines := strings.Split(to, "\n")
+	n := len(fromLines)
+	if len(toLines) > n {
+		n = len(toLines)
+	}
+	k := int(progress * float64(n))
+
+	lines := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		switch {
+		case i < k && i < len(toLines):
+			lines = append(lines, toLines[i])
+		case i >= k && i < len(fromLines):
+			lines = append(lines, fromLines[i])
+		default:
+			lines = append(lines, "")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+const (
+	faint = "\x1b[2m"
+	reset = "\x1b[0m"
+)
+
+// reStyle matches the escape sequences styling the rendered slides
+var reStyle = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// slide shows the right of the old slide followed by the left of the new
+// one, the new slide taking up more of the width as the transition goes on
+func slide(from, to string, progress float64) string {
+	fromLines := strings.Split(from, "\n")
+	toLines := strings.Split(to, "\n")
+	n := len(fromLines)
+	if len(toLines) > n {
+		n = len(toLines)
+	}
+	width := lipgloss.Width(from)
+	if w := lipgloss.Width(to); w > width {
+		width = w
+	}
+	k := int(progress * float64(width))
+
+	lines := make([]string, n)
+	for i := range lines {
+		var fromLine, toLine string
+		if i < len(fromLines) {
+			fromLine = skip(fromLines[i], k)
+		}
+		if i < len(toLines) {
+			toLine = take(toLines[i], k)
+		}
+		padding := width - k - lipgloss.Width(fromLine)
+		if padding < 0 {
+			padding = 0
+		}
+		lines[i] = fromLine + reset + strings.Repeat(" ", padding) + toLine + reset
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fade shows the old slide faintly for the first half of the transition and
+// the new slide faintly for the second half
+func fade(from, to string, progress float64) string {
+	content := to
+	if progress < 0.5 {
+		content = from
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = faint + strings.ReplaceAll(line, reset, reset+faint) + reset
+	}
+	return strings.Join(lines, "\n")
+}
+
+// skip removes the first n columns of the line, keeping the escape sequences
+// styling the rest of the line
+func skip(line string, n int) string {
+	var b strings.Builder
+	for len(line) > 0 {
+		if loc := reStyle.FindStringIndex(line); loc != nil && loc[0] == 0 {
+			b.WriteString(line[:loc[1]])
+			line = line[loc[1]:]
+			continue
+		}
+		if n <= 0 {
+			b.WriteString(line)
+			break
+		}
+		r := []rune(line)[0]
+		n -= lipgloss.Width(string(r))
+		line = line[len(string(r)):]
+	}
+	return b.String()
+}
+
+// take returns the first n columns of the line, along with the escape
+// sequences styling them
+func take(line string, n int) string {
+	var b strings.Builder
+	for len(line) > 0 {
+		if loc := reStyle.FindStringIndex(line); loc != nil && loc[0] == 0 {
+			b.WriteString(line[:loc[1]])
+			line = line[loc[1]:]
+			continue
+		}
+		r := []rune(line)[0]
+		if n -= lipgloss.Width(string(r)); n < 0 {
+			break
+		}
+		b.WriteRune(r)
+		line = line[len(string(r)):]
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package transition_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/transition"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrame(t *testing.T) {
+	from := "a1\na2\na3\na4\na5\na6\na7\na8"
+	to := "b1\nb2\nb3\nb4\nb5\nb6\nb7\nb8"
+
+	tests := []struct {
+		name       string
+		transition string
+		frame      int
+		want       string
+	}{
+		{name: "First frame", transition: transition.Wipe, frame: 1, want: "b1\na2\na3\na4\na5\na6\na7\na8"},
+		{name: "Halfway", transition: transition.Wipe, frame: 4, want: "b1\nb2\nb3\nb4\na5\na6\na7\na8"},
+		{name: "Last frame", transition: transition.Wipe, frame: transition.Frames, want: to},
+		{name: "None", transition: transition.None, frame: 1, want: to},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, transition.Frame(tt.transition, from, to, tt.frame))
+		})
+	}
+}
+
+func TestFrame_differentHeights(t *testing.T) {
+	assert.Equal(t, "b1\na2", transition.Frame(transition.Wipe, "a1\na2", "b1", 4))
+	assert.Equal(t, "b1\n", transition.Frame(transition.Wipe, "a1", "b1\nb2", 4))
+}
+
+func TestFrame_slide(t *testing.T) {
+	from := "aaaa\naa"
+	to := "bbbb\nbbbb"
+
+	assert.Equal(t, "aa\x1b[0mbb\x1b[0m\n\x1b[0m  bb\x1b[0m", transition.Frame(transition.Slide, from, to, 4))
+	assert.Equal(t, to, transition.Frame(transition.Slide, from, to, transition.Frames))
+
+	// Styles of the slides are kept
+	styled := transition.Frame(transition.Slide, "\x1b[1maaaa\x1b[0m", "\x1b[3mbbbb\x1b[0m", 4)
+	assert.Equal(t, "\x1b[1maa\x1b[0m\x1b[0m\x1b[3mbb\x1b[0m", styled)
+}
+
+func TestFrame_fade(t *testing.T) {
+	from := "a1\n\x1b[1ma2\x1b[0m"
+	to := "b1"
+
+	assert.Equal(t, "\x1b[2ma1\x1b[0m\n\x1b[2m\x1b[1ma2\x1b[0m\x1b[2m\x1b[0m", transition.Frame(transition.Fade, from, to, 2))
+	assert.Equal(t, "\x1b[2mb1\x1b[0m", transition.Frame(transition.Fade, from, to, 6))
+	assert.Equal(t, to, transition.Frame(transition.Fade, from, to, transition.Frames))
+}
@@ -0,0 +1,60 @@
+// Package typist implements typing code into a tmux pane one key at a time,
+// as if it were typed by hand during a live demo
+package typist
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultDelay is the time between each key press when no delay is set
+const DefaultDelay = 50 * time.Millisecond
+
+// ErrNoTarget is returned when there is no pane to type in
+var ErrNoTarget = errors.New("no tmux pane to type in, set typeTarget in the metadata")
+
+// Keys returns the tmux arguments which send each key of the code to the
+// target pane in order, finishing with enter to run the code
+func Keys(target, code string) [][]string {
+	var keys [][]string
+	for _, line := range strings.Split(strings.TrimRight(code, "\n"), "\n") {
+		for _, r := range line {
+			key := string(r)
+			// tmux takes an argument ending in a semicolon as the end of the
+			// command, unless the semicolon is escaped
+			if key == ";" {
+				key = `\;`
+			}
+			keys = append(keys, []string{"send-keys", "-t", target, "-l", key})
+		}
+		keys = append(keys, []string{"send-keys", "-t", target, "Enter"})
+	}
+	return keys
+}
+
+// Type types the code into the target tmux pane waiting delay between each
+// key press
+func Type(target, code string, delay time.Duration) error {
+	if target == "" {
+		return ErrNoTarget
+	}
+	if delay <= 0 {
+		delay = DefaultDelay
+	}
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return errors.New("tmux not found in PATH")
+	}
+	for _, key := range Keys(target, code) {
+		out, err := exec.Command("tmux", key...).CombinedOutput()
+		if err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				return errors.New(msg)
+			}
+			return err
+		}
+		time.Sleep(delay)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package typist_test
+
+import (
+	"testing"
+
+	"github.com/maaslalani/slides/internal/typist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeys(t *testing.T) {
+	want := [][]string{
+		{"send-keys", "-t", "demo", "-l", "l"},
+		{"send-keys", "-t", "demo", "-l", "s"},
+		{"send-keys", "-t", "demo", "Enter"},
+		{"send-keys", "-t", "demo", "-l", "a"},
+		{"send-keys", "-t", "demo", "-l", `\;`},
+		{"send-keys", "-t", "demo", "-l", "b"},
+		{"send-keys", "-t", "demo", "Enter"},
+		{"send-keys", "-t", "demo", "-l", `\;`},
+		{"send-keys", "-t", "demo", "Enter"},
+	}
+	assert.Equal(t, want, typist.Keys("demo", "ls\na;b\n;\n"))
+}
+
+func TestType_noTarget(t *testing.T) {
+	assert.Equal(t, typist.ErrNoTarget, typist.Type("", "ls", 0))
+}
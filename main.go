@@ -1,47 +1,331 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maaslalani/slides/internal/announce"
+	"github.com/maaslalani/slides/internal/export"
+	"github.com/maaslalani/slides/internal/file"
+	"github.com/maaslalani/slides/internal/format"
+	"github.com/maaslalani/slides/internal/launcher"
+	"github.com/maaslalani/slides/internal/meta"
 	"github.com/maaslalani/slides/internal/model"
 	"github.com/maaslalani/slides/internal/navigation"
+	"github.com/maaslalani/slides/internal/rehearsal"
+	"github.com/maaslalani/slides/internal/resume"
+	"github.com/maaslalani/slides/styles"
+	"github.com/muesli/termenv"
 )
 
 func printError(err error) {
 	fmt.Fprintf(os.Stderr, `Error: %s
 Usage:
-  slides <file.md>
+  slides [flags] <file.md>
+  slides fmt [--diff] <file.md>...
 
 `, err.Error())
 }
 
+// variables are the name=value pairs of the repeatable --var flag
+type variables map[string]string
+
+func (v variables) String() string {
+	var pairs []string
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v variables) Set(pair string) error {
+	parts := strings.SplitN(pair, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("invalid variable %q, expected name=value", pair)
+	}
+	v[parts[0]] = parts[1]
+	return nil
+}
+
+// pageEnv is the environment variable setting the slide to open on
+const pageEnv = "SLIDES_PAGE"
+
+// themesEnv is the environment variable setting the directory of custom
+// themes
+const themesEnv = "SLIDES_THEMES"
+
 func main() {
 	var err error
-	var fileName string
 
-	if len(os.Args) > 1 {
-		fileName = os.Args[1]
+	rehearse := flag.Bool("rehearse", false, "record the time spent on each slide and print a summary on quit")
+	fromClipboard := flag.Bool("clipboard", false, "present the contents of the clipboard, reloading when it changes")
+	exec := flag.Bool("exec", false, "allow the slides to run code blocks and commands, as if allowExec were set in the metadata")
+	stream := flag.Bool("stream", false, "present the slides piped to stdin as they arrive, adding slides as more of them are piped in")
+	theme := flag.String("theme", "", "theme to present the slides with, overriding the metadata and $"+meta.ThemeEnv)
+	themesDir := flag.String("themes", "", "directory of JSON glamour styles which can be used as themes by name, overriding $"+themesEnv)
+	presenter := flag.Bool("presenter", false, "print the speaker notes of each slide to stderr, to follow along in a second terminal")
+	noContact := flag.Bool("no-contact", false, "do not append a closing slide with the contact information from the metadata")
+	noWatch := flag.Bool("no-watch", false, "do not reload the slides when the file changes")
+	announcePath := flag.String("announce", "", "file or unix socket to write the current slide to as JSON whenever it changes")
+	console := flag.String("console", "", "show the presenter console, announcing the current slide to a unix socket followed with --follow")
+	follow := flag.String("follow", "", "follow the presenter console announcing to the unix socket, for the audience")
+	exportFormat := flag.String("export", "", "export the slides to another format ("+strings.Join(export.Names(), ", ")+") instead of presenting")
+	output := flag.String("output", "", "file to write the export to, defaults to stdout")
+	page := flag.Int("page", 0, "slide to open on, starting at 1, overriding $"+pageEnv)
+	resumePage := flag.Bool("resume", false, "open on the slide the deck was last left on, remembering it on quit")
+	plain := flag.Bool("plain", false, "render the slides as plain text without colors or borders, also set by $NO_COLOR")
+	separator := flag.String("separator", "", "line separating the slides instead of ---, overriding the metadata")
+	check := flag.Bool("check", false, "check the slides for problems and exit, with a non-zero status if there are any")
+	printOnExit := flag.Bool("print-on-exit", false, "print the rendered current slide to stdout on quit")
+	vars := variables{}
+	flag.Var(vars, "var", "set a variable used in the slides as name=value, overriding the metadata (repeatable)")
+	flag.Parse()
+
+	if flag.Arg(0) == "fmt" {
+		err = formatFiles(flag.Args()[1:])
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fileName := flag.Arg(0)
+
+	// Decks opened from a manifest share the announcer
+	var announcer *announce.Announcer
+	if *announcePath != "" {
+		announcer = announce.New(*announcePath)
+	}
+	// The console announces to the audience instead
+	if *console != "" {
+		announcer = announce.New(*console)
 	}
 
-	presentation := model.Model{
-		Page:     0,
-		Date:     time.Now().Format("2006-01-02"),
-		FileName: fileName,
-		Search:   navigation.NewSearch(),
+	// The directory of themes is kept absolute for decks in other
+	// directories
+	themes := *themesDir
+	if themes == "" {
+		themes = os.Getenv(themesEnv)
+	}
+	if themes != "" {
+		themes, _ = filepath.Abs(themes)
+	}
+
+	newDeck := func(fileName string) model.Model {
+		presentation := model.Model{
+			Page:     0,
+			Date:     time.Now().Format("2006-01-02"),
+			FileName: fileName,
+			Search:   navigation.NewSearch(),
+			Finder:   navigation.NewFinder(),
+		}
+		presentation.ThemeName = *theme
+		presentation.ThemesDir = themes
+		if _, err := os.Stat(*theme); err == nil {
+			presentation.ThemeName, _ = filepath.Abs(*theme)
+		}
+		presentation.Clipboard = *fromClipboard
+		presentation.Stream = *stream
+		presentation.Exec = *exec
+		presentation.NoWatch = *noWatch
+		presentation.NoContact = *noContact
+		presentation.Separator = *separator
+		presentation.Plain = *plain || termenv.EnvNoColor()
+		presentation.Presenter = *presenter
+		presentation.Announcer = announcer
+		presentation.Console = *console != ""
+		presentation.Vars = vars
+		if *rehearse {
+			presentation.Rehearsal = rehearsal.New(0, time.Now())
+		}
+		return presentation
+	}
+
+	if launcher.IsManifest(fileName) {
+		// These flags act once the presentation is quit, which decks of a
+		// manifest never are
+		if *rehearse || *resumePage || *printOnExit {
+			printError(errors.New("--rehearse, --resume and --print-on-exit can not be used with a manifest"))
+			os.Exit(1)
+		}
+		manifest, err := launcher.ReadManifest(fileName)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		p := tea.NewProgram(launcher.New(manifest, newDeck), tea.WithAltScreen(), tea.WithMouseCellMotion())
+		err = p.Start()
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	presentation := newDeck(fileName)
+	// The page is clamped to the slides once they are loaded
+	resumeFile, _ := resume.File()
+	start := *page
+	if start == 0 {
+		start, _ = strconv.Atoi(os.Getenv(pageEnv))
+	}
+	if start > 0 {
+		presentation.Page = start - 1
+	} else if *resumePage && fileName != "" {
+		presentation.Page, _ = resume.Load(resumeFile, fileName)
 	}
 	err = presentation.Load()
 	if err != nil {
 		printError(err)
 		os.Exit(1)
 	}
+	// The rehearsal starts on the slide the deck opens on
+	if presentation.Rehearsal != nil {
+		presentation.Rehearsal = rehearsal.New(presentation.Page, time.Now())
+	}
+
+	if *check {
+		if !checkDeck(presentation) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *exportFormat != "" {
+		err = exportDeck(presentation, *exportFormat, *output)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *follow != "" {
+		listener, err := announce.Listen(*follow)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+		defer listener.Close()
+		presentation.Follow = listener.Slides
+	}
 
-	p := tea.NewProgram(presentation, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	err = p.Start()
+	options := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if *printOnExit {
+		// The presentation is drawn on stderr so that only the printed
+		// slide is written to stdout, which may be piped
+		options = append(options, tea.WithOutput(os.Stderr))
+		presentation.Output = os.Stderr
+	}
+	p := tea.NewProgram(presentation, options...)
+	final, err := p.StartReturningModel()
 	if err != nil {
 		printError(err)
 		os.Exit(1)
 	}
+
+	if *resumePage && fileName != "" && resumeFile != "" {
+		if err := resume.Save(resumeFile, fileName, final.(model.Model).Page); err != nil {
+			printError(err)
+		}
+	}
+
+	if *printOnExit {
+		fmt.Fprintln(os.Stdout, final.(model.Model).RenderCurrent())
+	}
+
+	if presentation.Rehearsal != nil {
+		presentation.Rehearsal.Stop(time.Now())
+		fmt.Fprint(os.Stderr, presentation.Rehearsal.Summary())
+	}
+}
+
+// checkDeck prints the problems of the loaded presentation along with a
+// summary, returning whether it has none
+func checkDeck(presentation model.Model) bool {
+	problems, blocks := presentation.Check()
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, problem)
+	}
+	summary := fmt.Sprintf("%d slides, %d code blocks", len(presentation.Slides), blocks)
+	if len(problems) > 0 {
+		fmt.Printf("%s, %d problems\n", summary, len(problems))
+		return false
+	}
+	fmt.Printf("%s, OK\n", summary)
+	return true
+}
+
+// exportDeck writes the loaded presentation in the given format to output,
+// or stdout if no output is given
+func exportDeck(presentation model.Model, format, output string) error {
+	exporter, ok := export.Formats[format]
+	if !ok {
+		return fmt.Errorf("unknown export format %q, must be one of: %s", format, strings.Join(export.Names(), ", "))
+	}
+
+	style := styles.Config(presentation.ThemePath())
+	if presentation.CodeTheme != "" {
+		style.CodeBlock.Theme = presentation.CodeTheme
+		style.CodeBlock.Chroma = nil
+	}
+	deck := export.Deck{
+		Author:     presentation.Author,
+		Date:       presentation.Date,
+		Theme:      presentation.ThemeName,
+		Paging:     presentation.Paging,
+		Background: presentation.Background,
+		Slides:     presentation.Slides,
+		Style:      style,
+		Render:     presentation.RenderSlides,
+	}
+	md := exporter(deck, func(warning string) {
+		fmt.Fprintln(os.Stderr, "Warning:", warning)
+	})
+
+	if output == "" {
+		_, err := fmt.Fprintln(os.Stdout, md)
+		return err
+	}
+	return os.WriteFile(output, []byte(md+"\n"), 0o644)
+}
+
+// formatFiles formats the slides files in place, or prints the changes when
+// run with --diff
+func formatFiles(args []string) error {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	diff := flags.Bool("diff", false, "print the changes instead of writing them to the files")
+	_ = flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return errors.New("no files to format")
+	}
+	for _, path := range flags.Args() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		formatted := format.Format(string(content))
+		if *diff {
+			fmt.Print(format.Diff(path, string(content), formatted))
+			continue
+		}
+		if formatted == string(content) {
+			continue
+		}
+		err = file.Write(path, []byte(formatted))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -3,18 +3,26 @@ package styles
 
 import (
 	_ "embed"
+	"encoding/json"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
+	chromastyles "github.com/alecthomas/chroma/styles"
 	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
 )
 
 const (
 	salmon = lipgloss.Color("#E8B4BC")
+	green  = lipgloss.Color("#A6E3A1")
+	red    = lipgloss.Color("#F38BA8")
 )
 
 var (
@@ -24,6 +32,32 @@ var (
 	Slide  = lipgloss.NewStyle().Padding(1)
 	Status = lipgloss.NewStyle().Padding(1)
 	Search = lipgloss.NewStyle().Faint(true).Align(lipgloss.Left).MarginLeft(2)
+	Toast  = lipgloss.NewStyle().Foreground(salmon).Align(lipgloss.Left).MarginLeft(2)
+
+	Selected   = lipgloss.NewStyle().Foreground(salmon).MarginLeft(2)
+	Unselected = lipgloss.NewStyle().Faint(true).MarginLeft(2)
+
+	RunPending = lipgloss.NewStyle().Faint(true).MarginRight(2)
+	RunSuccess = lipgloss.NewStyle().Foreground(green).MarginRight(2)
+	RunFailure = lipgloss.NewStyle().Foreground(red).MarginRight(2)
+
+	Sidebar          = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderLeft(true).Padding(1, 1)
+	Quote            = lipgloss.NewStyle().BorderStyle(lipgloss.ThickBorder()).BorderLeft(true).BorderForeground(salmon).PaddingLeft(1).Italic(true)
+	QuoteAttribution = lipgloss.NewStyle().Foreground(salmon).PaddingLeft(2)
+
+	Notes = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderTop(true).BorderForeground(salmon).Padding(0, 1)
+
+	CanvasFill = lipgloss.Color("#3C3C3C")
+
+	ProgressBar = lipgloss.NewStyle().Foreground(salmon)
+
+	OverviewCell     = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#3C3C3C")).Width(20)
+	OverviewSelected = OverviewCell.Copy().BorderForeground(salmon).Foreground(salmon)
+
+	Overlay = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(salmon).Padding(1, 2)
+
+	ErrorPanel = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(red).Padding(0, 1)
+	ErrorTitle = lipgloss.NewStyle().Foreground(red).Bold(true)
 )
 
 var (
@@ -49,6 +83,141 @@ func JoinVertical(top, bottom string, height int) string {
 	return top + fill + bottom
 }
 
+// ParseSize parses a size written as "WIDTHxHEIGHT", such as 80x24
+func ParseSize(size string) (int, int, bool) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(size)), "x")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	width, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || width <= 0 {
+		return 0, 0, false
+	}
+	height, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || height <= 0 {
+		return 0, 0, false
+	}
+	return width, height, true
+}
+
+// ParsePadding parses a padding written as "VERTICAL HORIZONTAL", such as
+// "1 2", or as a single padding on every side
+func ParsePadding(padding string) (int, int, bool) {
+	parts := strings.Fields(padding)
+	if len(parts) == 1 {
+		parts = append(parts, parts[0])
+	}
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	vertical, err := strconv.Atoi(parts[0])
+	if err != nil || vertical < 0 {
+		return 0, 0, false
+	}
+	horizontal, err := strconv.Atoi(parts[1])
+	if err != nil || horizontal < 0 {
+		return 0, 0, false
+	}
+	return vertical, horizontal, true
+}
+
+// reStyle matches the escape sequences styling text
+var reStyle = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Fit tightens the blank lines of content which is taller than height so
+// that it fits, runs of blank lines are squeezed into one before blank lines
+// are removed altogether. The content may still be taller than height.
+func Fit(content string, height int) string {
+	lines := strings.Split(content, "\n")
+	if height <= 0 || len(lines) <= height {
+		return content
+	}
+	blank := func(line string) bool {
+		return strings.TrimSpace(reStyle.ReplaceAllString(line, "")) == ""
+	}
+
+	var squeezed []string
+	for i, line := range lines {
+		if i > 0 && blank(line) && blank(lines[i-1]) {
+			continue
+		}
+		squeezed = append(squeezed, line)
+	}
+	if len(squeezed) <= height {
+		return strings.Join(squeezed, "\n")
+	}
+
+	var packed []string
+	for _, line := range squeezed {
+		if !blank(line) {
+			packed = append(packed, line)
+		}
+	}
+	return strings.Join(packed, "\n")
+}
+
+// Paginate splits the content at line boundaries into pages which are at
+// most height lines tall
+func Paginate(content string, height int) []string {
+	lines := strings.Split(content, "\n")
+	if height <= 0 || len(lines) <= height {
+		return []string{content}
+	}
+
+	var pages []string
+	for len(lines) > height {
+		pages = append(pages, strings.Join(lines[:height], "\n"))
+		lines = lines[height:]
+	}
+	return append(pages, strings.Join(lines, "\n"))
+}
+
+// Themes lists the names of the built-in themes
+var Themes = []string{"default", "dark", "light", "ascii", "notty"}
+
+// NextTheme returns the theme that follows current when cycling through the
+// built-in themes. A custom theme is cycled as if it came before the
+// built-in themes.
+func NextTheme(current string) string {
+	for i, theme := range Themes {
+		if theme == current {
+			return Themes[(i+1)%len(Themes)]
+		}
+	}
+	return Themes[0]
+}
+
+// Exists reports whether the theme is a built-in theme, a URL or a file
+// which exists
+func Exists(theme string) bool {
+	for _, t := range Themes {
+		if t == theme {
+			return true
+		}
+	}
+	if strings.HasPrefix(theme, "http") {
+		return true
+	}
+	_, err := os.Stat(theme)
+	return err == nil
+}
+
+// FindTheme returns the path of the theme named name in the directory of
+// themes, which is either a file with that name or the name followed by
+// .json. Names which are paths are never found in the directory.
+func FindTheme(dir, name string) (string, bool) {
+	if dir == "" || name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	for _, file := range []string{name + ".json", name} {
+		path := filepath.Join(dir, file)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
 // SelectTheme picks a glamour style config based
 // on the theme provided in the markdown header
 func SelectTheme(theme string) glamour.TermRendererOption {
@@ -62,25 +231,7 @@ func SelectTheme(theme string) glamour.TermRendererOption {
 	case "notty":
 		return glamour.WithStyles(glamour.NoTTYStyleConfig)
 	default:
-		var themeReader io.Reader
-		var err error
-		if strings.HasPrefix(theme, "http") {
-			var resp *http.Response
-			resp, err = http.Get(theme)
-			if err != nil {
-				return getDefaultTheme()
-			}
-			defer resp.Body.Close()
-			themeReader = resp.Body
-		} else {
-			file, err := os.Open(theme)
-			if err != nil {
-				return getDefaultTheme()
-			}
-			defer file.Close()
-			themeReader = file
-		}
-		bytes, err := io.ReadAll(themeReader)
+		bytes, err := readTheme(theme)
 		if err == nil {
 			return glamour.WithStylesFromJSONBytes(bytes)
 		}
@@ -89,6 +240,67 @@ func SelectTheme(theme string) glamour.TermRendererOption {
 	}
 }
 
+// readTheme reads the JSON of a custom theme from a file or URL
+func readTheme(theme string) ([]byte, error) {
+	var themeReader io.Reader
+	if strings.HasPrefix(theme, "http") {
+		resp, err := http.Get(theme)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		themeReader = resp.Body
+	} else {
+		file, err := os.Open(theme)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		themeReader = file
+	}
+	return io.ReadAll(themeReader)
+}
+
+// Config returns the style config of the theme, for rendering the slides
+// outside of the terminal. The default theme is used for themes which can't
+// be read.
+func Config(theme string) ansi.StyleConfig {
+	switch theme {
+	case "ascii":
+		return glamour.ASCIIStyleConfig
+	case "light":
+		return glamour.LightStyleConfig
+	case "dark":
+		return glamour.DarkStyleConfig
+	case "notty":
+		return glamour.NoTTYStyleConfig
+	}
+	var config ansi.StyleConfig
+	if bytes, err := readTheme(theme); err == nil && json.Unmarshal(bytes, &config) == nil {
+		return config
+	}
+	_ = json.Unmarshal(DefaultTheme, &config)
+	return config
+}
+
+// CodeThemeExists reports whether name is a chroma syntax highlighting style
+func CodeThemeExists(name string) bool {
+	_, ok := chromastyles.Registry[name]
+	return ok
+}
+
+// WithCodeTheme picks the glamour style config of the theme, with its code
+// blocks highlighted by the chroma style of the given name instead
+func WithCodeTheme(theme, code string) glamour.TermRendererOption {
+	config := Config(theme)
+	if theme == "default" && !termenv.HasDarkBackground() {
+		config = glamour.LightStyleConfig
+	}
+	config.CodeBlock.Theme = code
+	config.CodeBlock.Chroma = nil
+	return glamour.WithStyles(config)
+}
+
 func getDefaultTheme() glamour.TermRendererOption {
 	if termenv.EnvNoColor() {
 		return glamour.WithStyles(glamour.NoTTYStyleConfig)
@@ -1,11 +1,13 @@
 package styles_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/glamour/ansi"
 	"github.com/maaslalani/slides/styles"
+	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,3 +74,158 @@ func TestSelectTheme_file(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig(t *testing.T) {
+	assert.Equal(t, glamour.LightStyleConfig, styles.Config("light"))
+
+	custom := styles.Config("./theme.json")
+	assert.NotNil(t, custom.Document.Color)
+
+	// Themes which can't be read fall back to the default theme
+	assert.Equal(t, styles.Config("default"), styles.Config("./someinvalidfile.toml"))
+	assert.NotNil(t, styles.Config("default").H1.Color)
+}
+
+func TestExists(t *testing.T) {
+	assert.True(t, styles.Exists("dark"))
+	assert.True(t, styles.Exists("https://example.com/theme.json"))
+	assert.True(t, styles.Exists("styles.go"))
+	assert.False(t, styles.Exists("missing.json"))
+}
+
+func TestFindTheme(t *testing.T) {
+	path, ok := styles.FindTheme(".", "theme")
+	assert.True(t, ok)
+	assert.Equal(t, "theme.json", path)
+
+	path, ok = styles.FindTheme(".", "theme.json")
+	assert.True(t, ok)
+	assert.Equal(t, "theme.json", path)
+
+	_, ok = styles.FindTheme(".", "missing")
+	assert.False(t, ok)
+	_, ok = styles.FindTheme(".", "./theme.json")
+	assert.False(t, ok)
+	_, ok = styles.FindTheme("", "theme")
+	assert.False(t, ok)
+}
+
+func TestCodeThemeExists(t *testing.T) {
+	assert.True(t, styles.CodeThemeExists("monokai"))
+	assert.False(t, styles.CodeThemeExists("missing"))
+}
+
+func TestWithCodeTheme(t *testing.T) {
+	render := func(theme glamour.TermRendererOption) string {
+		r, err := glamour.NewTermRenderer(theme, glamour.WithColorProfile(termenv.TrueColor))
+		assert.NoError(t, err)
+		out, err := r.Render("# Title\n\n```go\nfunc main() {}\n```")
+		assert.NoError(t, err)
+		return out
+	}
+
+	light := render(styles.SelectTheme("light"))
+	monokai := render(styles.WithCodeTheme("light", "monokai"))
+	assert.NotEqual(t, light, monokai)
+	// The prose keeps the colors of the theme
+	assert.Equal(t, strings.Split(light, "\n")[1], strings.Split(monokai, "\n")[1])
+}
+
+func TestNextTheme(t *testing.T) {
+	tests := []struct {
+		current string
+		want    string
+	}{
+		{current: "default", want: "dark"},
+		{current: "dark", want: "light"},
+		{current: "notty", want: "default"},
+		{current: "./theme.json", want: "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.current, func(t *testing.T) {
+			assert.Equal(t, tt.want, styles.NextTheme(tt.current))
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size          string
+		width, height int
+		ok            bool
+	}{
+		{size: "80x24", width: 80, height: 24, ok: true},
+		{size: " 100 X 30 ", width: 100, height: 30, ok: true},
+		{size: "80", ok: false},
+		{size: "0x24", ok: false},
+		{size: "widexhigh", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			width, height, ok := styles.ParseSize(tt.size)
+			assert.Equal(t, tt.width, width)
+			assert.Equal(t, tt.height, height)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestParsePadding(t *testing.T) {
+	tests := []struct {
+		padding              string
+		vertical, horizontal int
+		ok                   bool
+	}{
+		{padding: "2 4", vertical: 2, horizontal: 4, ok: true},
+		{padding: " 0 ", vertical: 0, horizontal: 0, ok: true},
+		{padding: "1 2 3", ok: false},
+		{padding: "-1 2", ok: false},
+		{padding: "wide", ok: false},
+		{padding: "", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.padding, func(t *testing.T) {
+			vertical, horizontal, ok := styles.ParsePadding(tt.padding)
+			assert.Equal(t, tt.vertical, vertical)
+			assert.Equal(t, tt.horizontal, horizontal)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		height  int
+		want    string
+	}{
+		{name: "Fits", content: "a\n\n\nb", height: 4, want: "a\n\n\nb"},
+		{name: "Squeezed", content: "a\n\n\x1b[0m \x1b[0m\nb\n\nc", height: 5, want: "a\n\nb\n\nc"},
+		{name: "Packed", content: "a\n\n\nb\n\nc", height: 3, want: "a\nb\nc"},
+		{name: "Overflows", content: "a\nb\nc", height: 2, want: "a\nb\nc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, styles.Fit(tt.content, tt.height))
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		height  int
+		want    []string
+	}{
+		{name: "Fits", content: "a\nb", height: 2, want: []string{"a\nb"}},
+		{name: "Overflows", content: "a\nb\nc\nd\ne", height: 2, want: []string{"a\nb", "c\nd", "e"}},
+		{name: "No height", content: "a\nb", height: 0, want: []string{"a\nb"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, styles.Paginate(tt.content, tt.height))
+		})
+	}
+}